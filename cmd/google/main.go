@@ -21,7 +21,10 @@ func run() error {
 		Short: "Simple Google API server",
 	}
 
+	root.AddCommand(commands.Crawl())
+	root.AddCommand(commands.DeadLetters())
 	root.AddCommand(commands.Index())
+	root.AddCommand(commands.Links())
 	root.AddCommand(commands.Search())
 	root.AddCommand(commands.Serve())
 
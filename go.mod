@@ -5,6 +5,7 @@ go 1.22.7
 require (
 	github.com/aaaton/golem/v4 v4.0.1
 	github.com/aaaton/golem/v4/dicts/en v1.0.1
+	github.com/andybalholm/brotli v1.2.0
 	github.com/hashicorp/go-cleanhttp v0.5.2
 	github.com/jdkato/prose/v2 v2.0.0
 	github.com/mattn/go-sqlite3 v1.14.23
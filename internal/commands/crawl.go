@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joshuarubin/brightwave-google/internal/scope"
+	"github.com/joshuarubin/brightwave-google/pkg/client"
+	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
+)
+
+type crawl struct {
+	cfg client.Config
+}
+
+// Crawl returns the crawl cobra command
+func Crawl() *cobra.Command {
+	var cr crawl
+
+	cmd := cobra.Command{
+		Use:   "crawl crawl-id",
+		Short: "Show the progress of a crawl started by index",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cr.crawl(cmd.Context(), args...)
+		},
+	}
+
+	cr.flags(&cmd)
+
+	return &cmd
+}
+
+// flags sets the flags for the crawl command
+func (cr *crawl) flags(cmd *cobra.Command) {
+	cr.cfg.Flags(cmd)
+}
+
+var ErrCrawlIDRequired = errors.New("crawl id is required")
+
+func (cr *crawl) crawl(ctx context.Context, args ...string) error {
+	if len(args) < 1 {
+		return ErrCrawlIDRequired
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing crawl id: %w", err)
+	}
+
+	c, err := client.New(cr.cfg)
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+
+	resp, err := c.GetCrawl(ctx, &pb.GetCrawlRequest{
+		CrawlId: id,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting crawl: %w", err)
+	}
+
+	cw := resp.GetCrawl()
+
+	const (
+		minwidth = 0
+		tabwidth = 8
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	w := tabwriter.NewWriter(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
+	defer w.Flush()
+
+	state := "done"
+	if cw.GetQueued() > 0 {
+		state = "crawling"
+	}
+	if cw.GetTruncated() {
+		state += ", truncated (a budget was exhausted, not every url in scope was crawled)"
+	}
+
+	fmt.Fprintf(w, "Crawl\t%d\n", cw.GetId())
+	fmt.Fprintf(w, "State\t%s\n", state)
+	fmt.Fprintf(w, "Origin\t%s\n", cw.GetOrigin())
+	fmt.Fprintf(w, "Max depth\t%d\n", cw.GetK())
+	fmt.Fprintf(w, "Scope\t%s\n", scopeString(cw.GetScope()))
+	fmt.Fprintf(w, "Started\t%s\n", cw.GetCreatedAt().AsTime().Local().Format(time.DateTime))
+	if cw.Deadline != nil {
+		fmt.Fprintf(w, "Deadline\t%s\n", cw.GetDeadline().AsTime().Local().Format(time.DateTime))
+	}
+	fmt.Fprintf(w, "Pages\t%d%s\n", cw.GetPages(), limit(cw.GetMaxPages()))
+	if cw.GetMaxPagesPerHost() > 0 {
+		fmt.Fprintf(w, "Max pages per host\t%d\n", cw.GetMaxPagesPerHost())
+	}
+	fmt.Fprintf(w, "Queued\t%d\n", cw.GetQueued())
+	fmt.Fprintf(w, "Dead letters\t%d\n", cw.GetDeadLetters())
+
+	return nil
+}
+
+// scopeString describes the scope of a crawl
+func scopeString(s *pb.Scope) string {
+	ret := scope.ModeAny.String()
+	for mode, m := range scopeModes {
+		if m == s.GetMode() {
+			ret = mode.String()
+		}
+	}
+
+	if len(s.GetInclude()) > 0 {
+		ret += ", include " + strings.Join(s.GetInclude(), " ")
+	}
+
+	if len(s.GetExclude()) > 0 {
+		ret += ", exclude " + strings.Join(s.GetExclude(), " ")
+	}
+
+	return ret
+}
+
+// limit describes a budget, which is unlimited if 0
+func limit(n uint32) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (max %d)", n)
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joshuarubin/brightwave-google/pkg/client"
+	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
+)
+
+type deadLetters struct {
+	cfg client.Config
+}
+
+// DeadLetters returns the dead-letters cobra command
+func DeadLetters() *cobra.Command {
+	var d deadLetters
+
+	cmd := cobra.Command{
+		Use:   "dead-letters",
+		Short: "Manage urls that could not be fetched",
+	}
+
+	list := cobra.Command{
+		Use:   "list",
+		Short: "List urls that have exhausted their retries",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return d.list(cmd.Context())
+		},
+	}
+
+	redrive := cobra.Command{
+		Use:   "redrive [url...]",
+		Short: "Return the given urls, or all dead letters if none are given, to the queue",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return d.redrive(cmd.Context(), args...)
+		},
+	}
+
+	d.flags(&list)
+	d.flags(&redrive)
+
+	cmd.AddCommand(&list)
+	cmd.AddCommand(&redrive)
+
+	return &cmd
+}
+
+// flags sets the flags for the dead-letters subcommands
+func (d *deadLetters) flags(cmd *cobra.Command) {
+	d.cfg.Flags(cmd)
+}
+
+func (d *deadLetters) list(ctx context.Context) error {
+	c, err := client.New(d.cfg)
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+
+	resp, err := c.ListDeadLetters(ctx, &pb.ListDeadLettersRequest{})
+	if err != nil {
+		return fmt.Errorf("error listing dead letters: %w", err)
+	}
+
+	if len(resp.GetDeadLetters()) == 0 {
+		fmt.Fprintln(os.Stderr, "No dead letters found")
+		return nil
+	}
+
+	const (
+		minwidth = 0
+		tabwidth = 8
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	w := tabwriter.NewWriter(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "URL\tAttempts\tCreated\tError\n")
+	for _, l := range resp.GetDeadLetters() {
+		created := l.GetCreatedAt().AsTime().Local().Format(time.DateTime)
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", l.GetUrl(), l.GetAttempts(), created, l.GetLastError())
+	}
+
+	return nil
+}
+
+func (d *deadLetters) redrive(ctx context.Context, urls ...string) error {
+	c, err := client.New(d.cfg)
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+
+	resp, err := c.RedriveDeadLetters(ctx, &pb.RedriveDeadLettersRequest{
+		Urls: urls,
+	})
+	if err != nil {
+		return fmt.Errorf("error redriving dead letters: %w", err)
+	}
+
+	fmt.Printf("Redrove %d urls\n", resp.GetCount())
+
+	return nil
+}
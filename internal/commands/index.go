@@ -6,15 +6,25 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/joshuarubin/brightwave-google/internal/scope"
 	"github.com/joshuarubin/brightwave-google/pkg/client"
 	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
 )
 
 type index struct {
-	cfg client.Config
+	cfg     client.Config
+	scope   string
+	include []string
+	exclude []string
+
+	maxPages        uint32
+	maxPagesPerHost uint32
+	timeLimit       time.Duration
 }
 
 // Index returns the index cobra command
@@ -37,11 +47,24 @@ func Index() *cobra.Command {
 // flags sets the flags for the index command
 func (i *index) flags(cmd *cobra.Command) {
 	i.cfg.Flags(cmd)
+	cmd.Flags().StringVar(&i.scope, "scope", "any", "hosts whose links are followed: any, host (only the host of the url) or domain (hosts under its registrable domain)")
+	cmd.Flags().StringArrayVar(&i.include, "include", nil, "only follow links to urls matching this glob, or regular expression if prefixed with re:, may be repeated")
+	cmd.Flags().StringArrayVar(&i.exclude, "exclude", nil, "don't follow links to urls matching this glob, or regular expression if prefixed with re:, may be repeated")
+	cmd.Flags().Uint32Var(&i.maxPages, "max-pages", 0, "maximum number of urls to crawl, unlimited if 0")
+	cmd.Flags().Uint32Var(&i.maxPagesPerHost, "max-pages-per-host", 0, "maximum number of urls on any one host to crawl, unlimited if 0")
+	cmd.Flags().DurationVar(&i.timeLimit, "time-limit", 0, "stop crawling once this much time has passed, unlimited if 0")
+}
+
+var scopeModes = map[scope.Mode]pb.ScopeMode{
+	scope.ModeAny:    pb.ScopeMode_SCOPE_MODE_UNSPECIFIED,
+	scope.ModeHost:   pb.ScopeMode_SCOPE_MODE_HOST,
+	scope.ModeDomain: pb.ScopeMode_SCOPE_MODE_DOMAIN,
 }
 
 var (
 	ErrURLRequired      = errors.New("url is required")
 	ErrMaxDepthRequired = errors.New("max depth is required")
+	ErrInvalidScope     = errors.New("scope must be any, host or domain")
 )
 
 func (i *index) index(ctx context.Context, args ...string) error {
@@ -62,18 +85,38 @@ func (i *index) index(ctx context.Context, args ...string) error {
 		return fmt.Errorf("error parsing max-depth: %w", err)
 	}
 
+	mode, err := scope.ParseMode(i.scope)
+	if err != nil {
+		return ErrInvalidScope
+	}
+
 	c, err := client.New(i.cfg)
 	if err != nil {
 		return fmt.Errorf("error creating client: %w", err)
 	}
 
-	_, err = c.Index(ctx, &pb.IndexRequest{
+	req := pb.IndexRequest{
 		Origin: u.String(),
 		K:      uint32(maxDepth),
-	})
+		Scope: &pb.Scope{
+			Mode:    scopeModes[mode],
+			Include: i.include,
+			Exclude: i.exclude,
+		},
+		MaxPages:        i.maxPages,
+		MaxPagesPerHost: i.maxPagesPerHost,
+	}
+
+	if i.timeLimit > 0 {
+		req.Deadline = timestamppb.New(time.Now().Add(i.timeLimit))
+	}
+
+	resp, err := c.Index(ctx, &req)
 	if err != nil {
 		return fmt.Errorf("error indexing url: %w", err)
 	}
 
+	fmt.Printf("Started crawl %d, see its progress with: google crawl %d\n", resp.GetCrawlId(), resp.GetCrawlId())
+
 	return nil
 }
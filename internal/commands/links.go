@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joshuarubin/brightwave-google/pkg/client"
+	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
+)
+
+type links struct {
+	cfg       client.Config
+	in        string
+	out       string
+	pageSize  uint32
+	pageToken string
+}
+
+// Links returns the links cobra command
+func Links() *cobra.Command {
+	var l links
+
+	cmd := cobra.Command{
+		Use:   "links (--in url | --out url)",
+		Short: "List the links to or from the given url",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return l.links(cmd.Context())
+		},
+	}
+
+	l.flags(&cmd)
+
+	return &cmd
+}
+
+// flags sets the flags for the links command
+func (l *links) flags(cmd *cobra.Command) {
+	l.cfg.Flags(cmd)
+	cmd.Flags().StringVar(&l.in, "in", "", "list the links from other pages to this url")
+	cmd.Flags().StringVar(&l.out, "out", "", "list the links found on the page at this url")
+	cmd.Flags().Uint32Var(&l.pageSize, "page-size", 0, "maximum number of links to return, the server default is used if 0")
+	cmd.Flags().StringVar(&l.pageToken, "page-token", "", "token, from a previous request for the same url, of the page of links to return")
+	cmd.MarkFlagsMutuallyExclusive("in", "out")
+	cmd.MarkFlagsOneRequired("in", "out")
+}
+
+func (l *links) links(ctx context.Context) error {
+	c, err := client.New(l.cfg)
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+
+	var (
+		items []*pb.Link
+		next  string
+	)
+
+	if l.in != "" {
+		resp, err := c.GetBacklinks(ctx, &pb.GetBacklinksRequest{
+			Url:       l.in,
+			PageSize:  l.pageSize,
+			PageToken: l.pageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("error getting backlinks: %w", err)
+		}
+		items, next = resp.GetLinks(), resp.GetNextPageToken()
+	} else {
+		resp, err := c.GetOutlinks(ctx, &pb.GetOutlinksRequest{
+			Url:       l.out,
+			PageSize:  l.pageSize,
+			PageToken: l.pageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("error getting outlinks: %w", err)
+		}
+		items, next = resp.GetLinks(), resp.GetNextPageToken()
+	}
+
+	if len(items) == 0 {
+		fmt.Fprintln(os.Stderr, "No links found")
+		return nil
+	}
+
+	const (
+		minwidth = 0
+		tabwidth = 8
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	w := tabwriter.NewWriter(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
+	defer func() {
+		w.Flush()
+		if next != "" {
+			fmt.Fprintf(os.Stderr, "\nNext page: --page-token %s\n", next)
+		}
+	}()
+
+	fmt.Fprintf(w, "Source\tTarget\tText\n")
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", item.GetSourceUrl(), item.GetTargetUrl(), printable(item.GetAnchorText()))
+	}
+
+	return nil
+}
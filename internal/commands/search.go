@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"log/slog"
 	"os"
 	"strings"
 	"text/tabwriter"
+	"unicode"
 
 	"github.com/spf13/cobra"
 
@@ -16,7 +18,9 @@ import (
 )
 
 type search struct {
-	cfg client.Config
+	cfg       client.Config
+	pageSize  uint32
+	pageToken string
 }
 
 // Search returns the search cobra command
@@ -33,12 +37,18 @@ func Search() *cobra.Command {
 
 	s.flags(&cmd)
 
+	// the query may contain negated terms, like "-foo", that must not be
+	// parsed as flags
+	cmd.Flags().SetInterspersed(false)
+
 	return &cmd
 }
 
 // flags sets the flags for the search command
 func (s *search) flags(cmd *cobra.Command) {
 	s.cfg.Flags(cmd)
+	cmd.Flags().Uint32Var(&s.pageSize, "page-size", 0, "maximum number of results to return, the server default is used if 0")
+	cmd.Flags().StringVar(&s.pageToken, "page-token", "", "token, from a previous search for the same query, of the page of results to return")
 }
 
 var ErrQueryRequired = errors.New("query is required")
@@ -55,7 +65,9 @@ func (s *search) search(ctx context.Context, args ...string) error {
 	}
 
 	resp, err := c.Search(ctx, &pb.SearchRequest{
-		Query: args[0],
+		Query:     strings.Join(args, " "),
+		PageSize:  s.pageSize,
+		PageToken: s.pageToken,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error searching: %v\n", err)
@@ -75,12 +87,72 @@ func (s *search) search(ctx context.Context, args ...string) error {
 		flags    = 0
 	)
 	w := tabwriter.NewWriter(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
-	defer w.Flush()
+	defer func() {
+		w.Flush()
+		fmt.Fprintf(os.Stderr, "\n%d results\n", resp.GetTotalEstimatedResults())
+		if token := resp.GetNextPageToken(); token != "" {
+			fmt.Fprintf(os.Stderr, "Next page: --page-token %s\n", token)
+		}
+	}()
+
+	bold := isTerminal(os.Stdout)
 
-	fmt.Fprintf(w, "URL\tDepth\tOrigins\n")
+	fmt.Fprintf(w, "URL\tTitle\tScore\tDepth\tOrigins\tSnippet\n")
 	for _, t := range resp.GetTriples() {
-		fmt.Fprintf(w, "%s\t%d\t%s\n", t.GetRelevantUrl(), t.GetDepth(), strings.Join(t.GetOriginUrls(), ","))
+		fmt.Fprintf(w, "%s\t%s\t%.3f\t%d\t%s\t%s\n", t.GetRelevantUrl(), printable(t.GetTitle()), t.GetScore(), t.GetDepth(), strings.Join(t.GetOriginUrls(), ","), renderSnippet(t.GetSnippet(), bold))
 	}
 
 	return nil
 }
+
+// renderSnippet converts the highlighted terms in a snippet to bold text, if
+// enabled, and unescapes the rest
+func renderSnippet(snippet string, bold bool) string {
+	start, end := "", ""
+	if bold {
+		start, end = "\x1b[1m", "\x1b[0m"
+	}
+
+	// the text is unescaped separately from the highlighting so that escape
+	// sequences in it are removed but those added here are kept
+	var b strings.Builder
+	for i, part := range strings.Split(snippet, "<b>") {
+		if i > 0 {
+			b.WriteString(start)
+		}
+
+		text, rest, ok := strings.Cut(part, "</b>")
+		b.WriteString(printable(html.UnescapeString(text)))
+		if ok {
+			b.WriteString(end)
+			b.WriteString(printable(html.UnescapeString(rest)))
+		}
+	}
+
+	return b.String()
+}
+
+// printable removes the control characters from text crawled from the web,
+// which could otherwise send escape sequences to the terminal. whitespace is
+// replaced by spaces so that it can't break up the table it is printed in.
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isTerminal reports whether the file is a terminal, rather than being
+// redirected to a file or pipe
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
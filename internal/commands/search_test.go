@@ -0,0 +1,24 @@
+package commands
+
+import "testing"
+
+func TestRenderSnippet(t *testing.T) {
+	tests := []struct {
+		snippet string
+		bold    bool
+		want    string
+	}{
+		{"a <b>b</b> c", false, "a b c"},
+		{"a <b>b</b> c", true, "a \x1b[1mb\x1b[0m c"},
+		{"x &lt;b&gt; &amp;", true, "x <b> &"},
+		{"\x1b]0;title\x07 <b>a\x1b[2J</b>", true, "]0;title \x1b[1ma[2J\x1b[0m"},
+		{"&#27;[31mred \u009b31m", false, "[31mred 31m"},
+		{"a\nb\tc\u0085d", false, "a b c d"},
+	}
+
+	for _, tt := range tests {
+		if got := renderSnippet(tt.snippet, tt.bold); got != tt.want {
+			t.Errorf("renderSnippet(%q, %v) = %q, want %q", tt.snippet, tt.bold, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultMaxBodyBytes is the most bytes of a response body that are read,
+// after it is decompressed
+const DefaultMaxBodyBytes = 10 << 20
+
+// acceptEncoding is sent with every page request. setting it disables the
+// transparent gzip decompression of the http transport so that every encoding
+// is decoded, and limited, the same way.
+const acceptEncoding = "gzip, deflate, br"
+
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// limitReader reads at most n bytes, recording whether the underlying reader
+// had more than that
+type limitReader struct {
+	r         io.Reader
+	n         int64
+	truncated bool
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		if !l.truncated {
+			var b [1]byte
+			if n, _ := io.ReadFull(l.r, b[:]); n > 0 {
+				l.truncated = true
+			}
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// decodeBody returns a reader of the response body with its content encoding
+// removed. it reads at most limit decoded bytes, which also protects against
+// decompression bombs, small responses that decode to enormous ones.
+func decodeBody(resp *http.Response, limit int64) (*limitReader, error) {
+	r := io.Reader(resp.Body)
+
+	// encodings are listed in the order they were applied
+	encodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		switch enc := strings.ToLower(strings.TrimSpace(encodings[i])); enc {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			r, err = gzip.NewReader(r)
+		case "deflate":
+			r, err = inflate(r)
+		case "br":
+			r = brotli.NewReader(r)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, enc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedEncoding, err)
+		}
+	}
+
+	return &limitReader{r: r, n: limit}, nil
+}
+
+// inflate decodes a deflate content encoding. it should be zlib wrapped, but
+// some servers send raw deflate data instead.
+func inflate(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+
+	return flate.NewReader(br), nil
+}
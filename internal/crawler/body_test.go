@@ -0,0 +1,146 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func deflated(t *testing.T, data []byte, wrap bool) []byte {
+	t.Helper()
+
+	var (
+		b bytes.Buffer
+		w io.WriteCloser
+	)
+	if wrap {
+		w = zlib.NewWriter(&b)
+	} else {
+		w, _ = flate.NewWriter(&b, flate.DefaultCompression)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func response(encoding string, body []byte) *http.Response {
+	resp := http.Response{
+		Header: http.Header{},
+		Body:   io.NopCloser(bytes.NewReader(body)),
+	}
+	if encoding != "" {
+		resp.Header.Set("Content-Encoding", encoding)
+	}
+	return &resp
+}
+
+func TestDecodeBody(t *testing.T) {
+	text := []byte("hello, world")
+
+	tests := []struct {
+		name      string
+		encoding  string
+		body      []byte
+		limit     int64
+		want      string
+		truncated bool
+	}{
+		{name: "identity", body: text, limit: 100, want: "hello, world"},
+		{name: "exact limit", body: text, limit: int64(len(text)), want: "hello, world"},
+		{name: "truncated", body: text, limit: 5, want: "hello", truncated: true},
+		{name: "gzip", encoding: "gzip", body: gzipped(t, text), limit: 100, want: "hello, world"},
+		{name: "zlib deflate", encoding: "deflate", body: deflated(t, text, true), limit: 100, want: "hello, world"},
+		{name: "raw deflate", encoding: "deflate", body: deflated(t, text, false), limit: 100, want: "hello, world"},
+		{name: "gzip then deflate", encoding: "gzip, deflate", body: deflated(t, gzipped(t, text), true), limit: 100, want: "hello, world"},
+		{name: "decoded size is limited", encoding: "gzip", body: gzipped(t, text), limit: 5, want: "hello", truncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := decodeBody(response(tt.encoding, tt.body), tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			if r.truncated != tt.truncated {
+				t.Errorf("got truncated %v, want %v", r.truncated, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestDecodeBodyBomb(t *testing.T) {
+	// a small response that decodes to 64 MiB
+	const size = 64 << 20
+
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	zeros := make([]byte, 1<<20)
+	for range size / len(zeros) {
+		if _, err := w.Write(zeros); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	const limit = 1 << 20
+
+	r, err := decodeBody(response("gzip", b.Bytes()), limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != limit || !r.truncated {
+		t.Errorf("read %d bytes, truncated %v, want %d bytes, truncated", n, r.truncated, limit)
+	}
+}
+
+func TestDecodeBodyUnsupported(t *testing.T) {
+	for _, encoding := range []string{"compress", "gzip"} {
+		// the gzip body isn't gzipped
+		_, err := decodeBody(response(encoding, []byte(strings.Repeat("x", 10))), 100)
+		if !errors.Is(err, ErrUnsupportedEncoding) {
+			t.Errorf("%s: got error %v, want %v", encoding, err, ErrUnsupportedEncoding)
+		}
+	}
+}
@@ -1,21 +1,30 @@
 package crawler
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"errors"
-	"io"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
-	"golang.org/x/net/html"
 
+	"github.com/joshuarubin/brightwave-google/internal/extract"
 	"github.com/joshuarubin/brightwave-google/internal/index"
 	"github.com/joshuarubin/brightwave-google/internal/queue"
+	"github.com/joshuarubin/brightwave-google/internal/robots"
+)
+
+const (
+	// Agent is the product token used to match robots.txt user-agent lines
+	Agent = "BrightwaveBot"
+
+	// UserAgent is sent with every request
+	UserAgent = "Mozilla/5.0 (compatible; " + Agent + "/1.0; +http://brightwave.io)"
 )
 
 type Crawler struct {
@@ -23,8 +32,11 @@ type Crawler struct {
 	client       *http.Client
 	stop         chan struct{}
 	fetchTimeout time.Duration
+	maxBodyBytes int64
 	index        *index.Index
 	queue        *queue.Queue
+	robots       *robots.Cache
+	extractors   *extract.Registry
 	logger       *slog.Logger
 }
 
@@ -33,22 +45,31 @@ type transport struct {
 }
 
 func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; BrightwaveBot/1.0; +http://brightwave.io)")
+	req.Header.Set("User-Agent", UserAgent)
 	return t.parent.RoundTrip(req)
 }
 
-func New(id int, fetchTimeout time.Duration, index *index.Index, queue *queue.Queue) *Crawler {
+// NewTransport returns a pooled http transport that identifies itself with
+// UserAgent
+func NewTransport() http.RoundTripper {
+	return &transport{
+		parent: cleanhttp.DefaultPooledTransport(),
+	}
+}
+
+func New(id int, fetchTimeout time.Duration, maxBodyBytes int64, index *index.Index, queue *queue.Queue, robots *robots.Cache, extractors *extract.Registry) *Crawler {
 	return &Crawler{
 		id:           id,
 		fetchTimeout: fetchTimeout,
+		maxBodyBytes: maxBodyBytes,
 		index:        index,
 		queue:        queue,
+		robots:       robots,
+		extractors:   extractors,
 		stop:         make(chan struct{}),
 		logger:       slog.With("crawler", id),
 		client: &http.Client{
-			Transport: &transport{
-				parent: cleanhttp.DefaultPooledTransport(),
-			},
+			Transport: NewTransport(),
 			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
 				// disable auto redirects
 				return http.ErrUseLastResponse
@@ -63,110 +84,243 @@ func (c *Crawler) Run(ctx context.Context) {
 		select {
 		case <-c.stop:
 			return
-		case msg := <-c.queue.Next(ctx):
-			c.handleMsg(ctx, msg)
+		case msg := <-c.queue.Next(ctx, c.id):
+			if err := c.handleMsg(ctx, msg); err != nil {
+				if ctx.Err() != nil {
+					// the fetch was interrupted by shutdown rather than
+					// failing, so it doesn't count as an attempt
+					c.queue.Release(context.WithoutCancel(ctx), msg)
+					continue
+				}
+
+				var retryAfter time.Duration
+				if serr := (*StatusError)(nil); errors.As(err, &serr) {
+					retryAfter = serr.RetryAfter
+				}
+				c.queue.Retry(ctx, msg, err, retryAfter)
+				continue
+			}
+			c.queue.Ack(ctx, msg)
 		}
 	}
 }
 
-func (c *Crawler) handleMsg(ctx context.Context, msg queue.Msg) {
+// handleMsg crawls the url in msg. an error is returned if the crawl failed and
+// should be tried again.
+func (c *Crawler) handleMsg(ctx context.Context, msg queue.Msg) error {
+	if msg.URL.Scheme != "http" && msg.URL.Scheme != "https" {
+		// retrying won't help
+		c.logger.Warn("crawler: unsupported url", "url", msg.URL.String())
+		return nil
+	}
+
+	if c.queue.Expired(ctx, msg) {
+		c.logger.Info("crawler: crawl deadline passed", "url", msg.URL.String(), "crawl_id", msg.CrawlID)
+		return nil
+	}
+
 	if !c.index.ShouldIndex(ctx, msg.URL, nil) {
 		c.logger.Info("crawler: not re-indexing", "url", msg.URL.String())
-		return
+		return nil
 	}
 
 	c.logger.Info("received", "url", msg.URL.String(), "origin", msg.Origin.String(), "depth", msg.Depth, "max_depth", msg.MaxDepth)
 	ctx, cancel := context.WithTimeout(ctx, c.fetchTimeout)
 	defer cancel()
 
-	resp, err := c.fetch(ctx, msg)
+	// the queue checked robots.txt when this was added, but the rules may have
+	// changed since then
+	allowed, err := c.robots.Allowed(ctx, msg.URL)
+	if err != nil {
+		return fmt.Errorf("error getting robots.txt: %w", err)
+	}
+
+	if !allowed {
+		c.logger.Info("crawler: disallowed by robots.txt", "url", msg.URL.String())
+		return nil
+	}
+
+	resp, aliases, err := c.fetch(ctx, msg)
+
+	var redirect *RedirectError
 	switch {
-	case errors.Is(err, ErrRedirectLoop):
-	case errors.Is(err, ErrMaxDepth):
-	case errors.Is(err, ErrRedirect):
+	case errors.As(err, &redirect):
+		return c.queueRedirect(ctx, msg, redirect)
+	case errors.Is(err, ErrOutOfScope):
+		c.logger.Info("crawler: skipping, redirect out of scope", "err", err, "url", msg.URL.String(), "crawl_id", msg.CrawlID)
+		return c.index.Skip(ctx, msg.URL, "", err.Error())
+	case errors.Is(err, ErrRedirectLoop),
+		errors.Is(err, ErrTooManyRedirects),
+		errors.Is(err, ErrBadRedirect),
+		errors.Is(err, ErrDisallowed):
+		// retrying won't help
+		c.logger.Warn("error fetching", "err", err, "url", msg.URL.String())
+		return nil
 	case err != nil:
 		c.logger.Warn("error fetching", "err", err, "url", msg.URL.String())
-	}
-	if err != nil {
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
-	if err = c.process(ctx, msg, resp.Body); err != nil {
+	page := index.Page{
+		URL:          msg.URL,
+		Origin:       msg.Origin,
+		Depth:        msg.Depth,
+		Aliases:      aliases,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
+	if len(aliases) > 0 {
+		page.URL = *index.CleanURL(resp.Request.URL)
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return c.notModified(ctx, msg, page)
+	}
+
+	if len(aliases) > 0 {
+		// the page is indexed under the url at the end of the redirect chain,
+		// which may have already been indexed when it was reached another way
+		if !c.index.ShouldIndex(ctx, page.URL, nil) {
+			c.logger.Info("crawler: not re-indexing redirect target", "url", msg.URL.String(), "target", page.URL.String())
+			return c.index.AddAliases(ctx, page.URL, aliases)
+		}
+	}
+
+	if err = c.process(ctx, msg, page, resp); err != nil {
 		c.logger.Warn("error processing", "err", err, "url", msg.URL.String())
+		return err
 	}
+
+	return nil
 }
 
-func (c *Crawler) process(ctx context.Context, msg queue.Msg, body io.Reader) error {
-	z := html.NewTokenizer(body)
-	tags := []string{}
-	var buf bytes.Buffer
-	for {
-		tt := z.Next()
-		switch tt {
-		case html.ErrorToken:
-			if errors.Is(z.Err(), io.EOF) {
-				c.logger.Info("processed", "url", msg.URL.String())
-				return c.index.Add(ctx, index.Page{
-					URL:    msg.URL,
-					Origin: msg.Origin,
-					Depth:  msg.Depth,
-				}, buf.Bytes())
-			}
-			return z.Err()
-		case html.TextToken:
-			if len(tags) == 0 {
-				continue
-			}
-			switch tags[len(tags)-1] {
-			case "script", "style":
-				// don't consider these as text data
-			default:
-				buf.Write(z.Text())
-				buf.WriteString(" ")
-			}
-		case html.StartTagToken:
-			t := z.Token()
-			tags = append(tags, t.Data)
-			if msg.Depth >= msg.MaxDepth {
-				// don't add links if they will be exceed max depth
-				continue
-			}
-			if t.Data == "a" {
-				for _, a := range t.Attr {
-					var link *url.URL
-					var err error
-					if a.Key == "href" {
-						switch {
-						case strings.HasPrefix(a.Val, "#"):
-							// ignore fragment only urls
-							continue
-						case strings.HasPrefix(a.Val, "/"): // also handles // prefix
-							link, err = msg.URL.Parse(a.Val)
-						default:
-							link, err = url.Parse(a.Val)
-						}
-						if err != nil {
-							c.logger.Warn("error parsing link", "err", err, "url", a.Val)
-							continue
-						}
-
-						c.enQueue(ctx, queue.Msg{
-							URL:      *link,
-							Origin:   msg.Origin,
-							Depth:    msg.Depth + 1,
-							MaxDepth: msg.MaxDepth,
-						})
-					}
-				}
-			}
-		case html.EndTagToken:
-			if len(tags) > 0 {
-				tags = tags[:len(tags)-1]
-			} else {
-				tags = nil
-			}
+// process extracts the text and links from the body of the page found at msg,
+// using the extractor for its media type. pages that can't be extracted are
+// skipped rather than retried.
+func (c *Crawler) process(ctx context.Context, msg queue.Msg, page index.Page, resp *http.Response) error {
+	limited, err := decodeBody(resp, c.maxBodyBytes)
+	if err != nil {
+		c.logger.Info("crawler: skipping, can't decode body", "err", err, "url", page.URL.String())
+		return c.index.Skip(ctx, page.URL, resp.Header.Get("Content-Type"), err.Error())
+	}
+
+	body := bufio.NewReader(limited)
+	mediaType := extract.MediaType(resp.Header.Get("Content-Type"), body)
+
+	e, ok := c.extractors.Get(mediaType)
+	if !ok {
+		c.logger.Info("crawler: skipping, unsupported content type", "url", page.URL.String(), "content_type", mediaType)
+		return c.index.Skip(ctx, page.URL, mediaType, "unsupported content type")
+	}
+
+	// text is transcoded to utf-8 before it is extracted
+	r, encoding := extract.Decode(body, resp.Header.Get("Content-Type"), mediaType)
+
+	doc, err := e.Extract(r, &page.URL)
+
+	// the body is cut off at the limit, what was read of it is still indexed
+	if page.Truncated = limited.truncated; page.Truncated {
+		c.logger.Info("crawler: body truncated", "url", page.URL.String(), "max_body_bytes", c.maxBodyBytes)
+	}
+
+	switch {
+	case errors.Is(err, extract.ErrUnsupported), errors.Is(err, extract.ErrMalformed):
+		c.logger.Info("crawler: skipping, can't extract document", "err", err, "url", page.URL.String(), "content_type", mediaType)
+		reason := err.Error()
+		if page.Truncated {
+			reason += ", body truncated"
+		}
+		return c.index.Skip(ctx, page.URL, mediaType, reason)
+	case err != nil:
+		// the body may have been cut off, so fetching it again could help
+		return err
+	}
+
+	doc.Robots = doc.Robots.Merge(robots.HeaderDirectives(Agent, resp.Header))
+	if canonical := extract.CanonicalHeader(resp.Header, &page.URL); canonical != nil {
+		doc.Canonical = canonical
+	}
+
+	c.logger.Info("processed", "url", page.URL.String(), "content_type", mediaType, "encoding", encoding)
+
+	return c.done(ctx, msg, doc, page)
+}
+
+// done enqueues the links of a processed document and indexes it, as allowed by
+// its robots directives
+func (c *Crawler) done(ctx context.Context, msg queue.Msg, doc *extract.Document, page index.Page) error {
+	page = doc.Page(page)
+
+	if doc.Robots.NoFollow {
+		c.logger.Info("crawler: not following links, nofollow", "url", page.URL.String())
+	}
+
+	var links []url.URL
+	for _, l := range page.Links {
+		if !l.NoFollow {
+			links = append(links, l.URL)
+		}
+	}
+	c.follow(ctx, msg, links)
+
+	if doc.Robots.NoIndex {
+		c.logger.Info("crawler: not indexing, noindex", "url", page.URL.String())
+		return nil
+	}
+
+	return c.index.Add(ctx, page, doc.Body)
+}
+
+// notModified handles a page that hasn't changed since it was last indexed. it
+// is kept without being reindexed, but its links are still crawled.
+func (c *Crawler) notModified(ctx context.Context, msg queue.Msg, page index.Page) error {
+	c.logger.Info("crawler: not modified", "url", page.URL.String())
+
+	links, err := c.index.Touch(ctx, page.URL)
+	if err != nil {
+		return err
+	}
+
+	if len(page.Aliases) > 0 {
+		if err = c.index.AddAliases(ctx, page.URL, page.Aliases); err != nil {
+			return err
+		}
+	}
+
+	c.follow(ctx, msg, links)
+
+	return nil
+}
+
+// follow enqueues the links found on the page at msg that are within the
+// scope of its crawl
+func (c *Crawler) follow(ctx context.Context, msg queue.Msg, links []url.URL) {
+	if msg.Depth >= msg.MaxDepth {
+		// don't add links if they will be exceed max depth
+		return
+	}
+
+	policy, err := c.queue.Scope(ctx, msg.CrawlID)
+	if err != nil {
+		c.logger.Warn("crawler: not following links, error getting scope", "err", err, "url", msg.URL.String(), "crawl_id", msg.CrawlID)
+		return
+	}
+
+	for _, link := range links {
+		if !policy.Allows(link) {
+			c.logger.Debug("crawler: not following link, out of scope", "url", link.String(), "crawl_id", msg.CrawlID)
+			continue
 		}
+
+		c.enQueue(ctx, queue.Msg{
+			URL:      link,
+			Origin:   msg.Origin,
+			Depth:    msg.Depth + 1,
+			MaxDepth: msg.MaxDepth,
+			CrawlID:  msg.CrawlID,
+		})
 	}
 }
 
@@ -174,59 +328,210 @@ func (c *Crawler) Stop() {
 	close(c.stop)
 }
 
+// MaxRedirects is the longest redirect chain that will be followed
+const MaxRedirects = 10
+
 var (
-	ErrRedirectLoop = errors.New("redirect loop detected")
-	ErrMaxDepth     = errors.New("max depth reached")
-	ErrRedirect     = errors.New("redirect found")
+	ErrRedirectLoop     = errors.New("redirect loop detected")
+	ErrTooManyRedirects = errors.New("too many redirects")
+	ErrBadRedirect      = errors.New("invalid redirect")
+	ErrDisallowed       = errors.New("disallowed by robots.txt")
+	ErrOutOfScope       = errors.New("redirect out of crawl scope")
 )
 
+// RedirectError is returned when a url redirects to another host. The target
+// isn't requested immediately, the crawler is only scheduled to request the
+// host it was given, so it is queued instead.
+type RedirectError struct {
+	URL url.URL
+
+	// the urls that redirected to it
+	Aliases []url.URL
+}
+
+func (e *RedirectError) Error() string {
+	return "redirect to another host: " + e.URL.String()
+}
+
+// StatusError is returned when a server responds with a status that indicates
+// the request should be retried later
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// retryAfter parses the Retry-After header, which may be either a number of
+// seconds or an http date
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+
+	return 0
+}
+
+// queueRedirect records the urls that redirected to another host as aliases of
+// the target and queues it at the same depth
+func (c *Crawler) queueRedirect(ctx context.Context, msg queue.Msg, redirect *RedirectError) error {
+	c.logger.Info("crawler: queueing redirect to another host", "url", msg.URL.String(), "location", redirect.URL.String())
+
+	if err := c.index.AddRedirect(ctx, redirect.URL, msg.Depth, redirect.Aliases); err != nil {
+		return err
+	}
+
+	c.enQueue(ctx, queue.Msg{
+		URL:      redirect.URL,
+		Origin:   msg.Origin,
+		Depth:    msg.Depth,
+		MaxDepth: msg.MaxDepth,
+		CrawlID:  msg.CrawlID,
+	})
+
+	return nil
+}
+
 func (c *Crawler) enQueue(ctx context.Context, msg queue.Msg) {
 	// do this in a goroutine to prevent deadlocks
-	if err := c.queue.Add(ctx, msg); err != nil {
+	if _, err := c.queue.Add(ctx, msg); err != nil {
 		c.logger.Warn("error enqueuing", "err", err, "url", msg.URL.String())
 	}
 }
 
-func (c *Crawler) fetch(ctx context.Context, msg queue.Msg) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, msg.URL.String(), nil)
-	if err != nil {
-		return nil, err
+// isRedirect reports whether the status code redirects to the url in the
+// Location header
+func isRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently,
+		http.StatusFound,
+		http.StatusSeeOther,
+		http.StatusTemporaryRedirect,
+		http.StatusPermanentRedirect:
+		return true
 	}
+	return false
+}
+
+// fetch the url in msg, following any redirects. the urls that redirected are
+// returned along with the response, whose request has the final url.
+func (c *Crawler) fetch(ctx context.Context, msg queue.Msg) (*http.Response, []url.URL, error) {
+	var (
+		u       = msg.URL
+		aliases []url.URL
+		seen    = map[string]bool{u.String(): true}
+	)
 
-	resp, err := c.client.Do(req)
+	policy, err := c.queue.Scope(ctx, msg.CrawlID)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("error getting scope: %w", err)
 	}
 
-	if v := resp.Header.Get("Location"); v != "" {
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+
+		// only get the page if it has changed since it was last indexed
+		etag, lastModified := c.index.Validators(ctx, u)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c.logger.Info("fetched", "url", u.String(), "status", resp.StatusCode)
+
+		if !isRedirect(resp.StatusCode) {
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+				resp.Body.Close()
+				return nil, nil, &StatusError{
+					StatusCode: resp.StatusCode,
+					RetryAfter: retryAfter(resp.Header),
+				}
+			}
+
+			return resp, aliases, nil
+		}
+
+		resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		next, err := u.Parse(location)
 		switch {
-		case v == msg.URL.String():
-			c.logger.Warn("redirect loop detected", "url", msg.URL.String(), "location", v)
-			resp.Body.Close()
-			// TODO(jrubin) index the redirect?
-			return nil, ErrRedirectLoop
-		case msg.Depth >= msg.MaxDepth:
-			c.logger.Warn("redirect found, max depth reached", "url", msg.URL.String(), "location", v)
-			resp.Body.Close()
-			// TODO(jrubin) index the redirect?
-			return nil, ErrMaxDepth
-		default:
-			u, err := url.Parse(v)
+		case location == "":
+			return nil, nil, fmt.Errorf("%w: no location", ErrBadRedirect)
+		case err != nil:
+			return nil, nil, fmt.Errorf("%w: %w", ErrBadRedirect, err)
+		case next.Scheme != "http" && next.Scheme != "https":
+			return nil, nil, fmt.Errorf("%w: unsupported url %s", ErrBadRedirect, next)
+		}
+		next = index.CleanURL(next)
+
+		if seen[next.String()] {
+			return nil, nil, fmt.Errorf("%w: %s", ErrRedirectLoop, next)
+		}
+		seen[next.String()] = true
+
+		if len(aliases) >= MaxRedirects {
+			return nil, nil, fmt.Errorf("%w: %s", ErrTooManyRedirects, next)
+		}
+
+		if !policy.Allows(*next) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrOutOfScope, next)
+		}
+
+		if next.Host != msg.URL.Host {
+			// a redirect back to a url that redirected here on a previous
+			// request, rather than in this chain, is also a loop
+			target, err := c.index.Resolve(ctx, *next)
 			if err != nil {
-				c.logger.Warn("error parsing url", "err", err, "url", v)
-				return nil, err
+				return nil, nil, err
+			}
+			if target != next.String() && (target == u.String() || seen[target]) {
+				return nil, nil, fmt.Errorf("%w: %s", ErrRedirectLoop, next)
+			}
+
+			return nil, nil, &RedirectError{
+				URL:     *next,
+				Aliases: append(aliases, u),
 			}
-			c.enQueue(ctx, queue.Msg{
-				URL:      *u,
-				Origin:   msg.Origin,
-				Depth:    msg.Depth + 1,
-				MaxDepth: msg.MaxDepth,
-			})
-			resp.Body.Close()
-			return nil, ErrRedirect
 		}
-	}
 
-	c.logger.Info("fetched", "url", msg.URL.String(), "status", resp.StatusCode)
-	return resp, nil
+		// redirects on the same host are followed immediately rather than
+		// being queued, so robots.txt of the new url has to be checked here
+		allowed, err := c.robots.Allowed(ctx, *next)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting robots.txt: %w", err)
+		}
+
+		if !allowed {
+			return nil, nil, fmt.Errorf("%w: %s", ErrDisallowed, next)
+		}
+
+		c.logger.Info("following redirect", "url", u.String(), "location", next.String())
+
+		aliases = append(aliases, u)
+		u = *next
+	}
 }
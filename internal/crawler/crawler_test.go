@@ -0,0 +1,243 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/extract"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/queue"
+	"github.com/joshuarubin/brightwave-google/internal/registrar"
+	"github.com/joshuarubin/brightwave-google/internal/robots"
+)
+
+type nopRegistrar struct{}
+
+func (nopRegistrar) Register(string, registrar.Callback, int) {}
+
+// newCrawler returns a crawler with its own database, and the database
+func newCrawler(t *testing.T) (*Crawler, *db.DB) {
+	t.Helper()
+
+	d, err := db.Init(context.Background(), filepath.Join(t.TempDir(), "test.db"), func(int, string, string, int64) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SQL.Close() })
+
+	i := index.New(d, time.Hour)
+	rc := robots.New(&http.Client{Transport: NewTransport()}, Agent, time.Hour)
+	q := queue.New(d, i, rc, queue.NewScheduler(0, 1), nopRegistrar{}, queue.Config{
+		LeaseDur:       time.Minute,
+		MaxAttempts:    3,
+		RetryBaseDelay: time.Second,
+		RetryMaxDelay:  time.Minute,
+	})
+
+	return New(0, time.Minute, 1<<20, i, q, rc, extract.New(Agent)), d
+}
+
+func crawl(t *testing.T, c *Crawler, raw string) {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.handleMsg(context.Background(), queue.Msg{URL: *u, Origin: *u, MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConditionalAlias(t *testing.T) {
+	const etag = `"v1"`
+
+	var (
+		mu          sync.Mutex
+		notModified = map[string]bool{}
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/target", http.StatusMovedPermanently)
+	})
+	page := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				mu.Lock()
+				notModified[r.URL.Path] = true
+				mu.Unlock()
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, body)
+		}
+	}
+	mux.HandleFunc("/target", page(`<html><body>hello</body></html>`))
+	mux.HandleFunc("/page", page(`<html><head><link rel="canonical" href="/canonical"></head><body>hello</body></html>`))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		name, path, stored string
+	}{
+		{"redirect", "/redirect", "/target"},
+		{"canonical", "/page", "/canonical"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, d := newCrawler(t)
+
+			crawl(t, c, srv.URL+tt.path)
+
+			var id int64
+			if err := d.SQL.QueryRow("SELECT id FROM pages WHERE url = ?", srv.URL+tt.stored).Scan(&id); err != nil {
+				t.Fatalf("page isn't stored under %s: %v", tt.stored, err)
+			}
+
+			// the page is due to be indexed again
+			stale := time.Now().UTC().Add(-2 * time.Hour)
+			if _, err := d.SQL.Exec("UPDATE pages SET modified_at = ?", stale); err != nil {
+				t.Fatal(err)
+			}
+
+			crawl(t, c, srv.URL+tt.path)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if len(notModified) == 0 {
+				t.Fatal("page wasn't requested conditionally")
+			}
+			clear(notModified)
+
+			var modified time.Time
+			if err := d.SQL.QueryRow("SELECT modified_at FROM pages WHERE id = ?", id).Scan(&modified); err != nil {
+				t.Fatal(err)
+			}
+			if !modified.After(stale) {
+				t.Error("unmodified page wasn't marked as indexed")
+			}
+		})
+	}
+}
+
+func TestRobotsDirectives(t *testing.T) {
+	mux := http.NewServeMux()
+	page := func(header, meta string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if header != "" {
+				w.Header().Set("X-Robots-Tag", header)
+			}
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, `<html><head>%s</head><body>hello <a href="%s/linked">linked</a></body></html>`, meta, r.URL.Path)
+		}
+	}
+	mux.HandleFunc("/meta", page("", `<meta name="robots" content="noindex, nofollow">`))
+	mux.HandleFunc("/agent", page("", `<meta name="`+Agent+`" content="none">`))
+	mux.HandleFunc("/header", page("noindex", ""))
+	mux.HandleFunc("/other", page("otherbot: noindex, nofollow", `<meta name="otherbot" content="none">`))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		path              string
+		indexed, followed bool
+	}{
+		{"/meta", false, false},
+		{"/agent", false, false},
+		{"/header", false, true},
+		{"/other", true, true},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			c, d := newCrawler(t)
+
+			crawl(t, c, srv.URL+tt.path)
+
+			var indexed, followed bool
+			err := d.SQL.QueryRow(`
+SELECT
+    EXISTS(SELECT 1 FROM pages WHERE url = ?1),
+    EXISTS(SELECT 1 FROM queue WHERE url = ?1 || '/linked')`, srv.URL+tt.path).Scan(&indexed, &followed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if indexed != tt.indexed {
+				t.Errorf("indexed %v, want %v", indexed, tt.indexed)
+			}
+			if followed != tt.followed {
+				t.Errorf("followed %v, want %v", followed, tt.followed)
+			}
+		})
+	}
+}
+
+func TestRedirectAliases(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/c", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body>hello</body></html>`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, d := newCrawler(t)
+
+	crawl(t, c, srv.URL+"/a")
+
+	rows, err := d.SQL.Query("SELECT url FROM pages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var pages []string
+	for rows.Next() {
+		var u string
+		if err = rows.Scan(&u); err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, u)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the page is only indexed under the end of the chain
+	if len(pages) != 1 || pages[0] != srv.URL+"/c" {
+		t.Fatalf("got pages %v, want only %s/c", pages, srv.URL)
+	}
+
+	for _, path := range []string{"/a", "/b"} {
+		var target string
+		if err = d.SQL.QueryRow("SELECT target_url FROM aliases WHERE url = ?", srv.URL+path).Scan(&target); err != nil {
+			t.Fatalf("%s isn't an alias: %v", path, err)
+		}
+		if target != srv.URL+"/c" {
+			t.Errorf("%s is an alias of %s, want %s/c", path, target, srv.URL)
+		}
+
+		// the redirecting urls aren't fetched again until the target is due
+		u, _ := url.Parse(srv.URL + path)
+		if c.index.ShouldIndex(context.Background(), *u, nil) {
+			t.Errorf("%s would be fetched again", path)
+		}
+	}
+}
@@ -24,50 +24,252 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
-	if q.dequeueStmt, err = db.PrepareContext(ctx, dequeue); err != nil {
-		return nil, fmt.Errorf("error preparing query Dequeue: %w", err)
+	if q.ackLeaseStmt, err = db.PrepareContext(ctx, ackLease); err != nil {
+		return nil, fmt.Errorf("error preparing query AckLease: %w", err)
+	}
+	if q.countCrawlHostPageStmt, err = db.PrepareContext(ctx, countCrawlHostPage); err != nil {
+		return nil, fmt.Errorf("error preparing query CountCrawlHostPage: %w", err)
+	}
+	if q.countCrawlPageStmt, err = db.PrepareContext(ctx, countCrawlPage); err != nil {
+		return nil, fmt.Errorf("error preparing query CountCrawlPage: %w", err)
+	}
+	if q.deleteAliasStmt, err = db.PrepareContext(ctx, deleteAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAlias: %w", err)
+	}
+	if q.deleteDeadLetterStmt, err = db.PrepareContext(ctx, deleteDeadLetter); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDeadLetter: %w", err)
+	}
+	if q.deleteFieldStmt, err = db.PrepareContext(ctx, deleteField); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteField: %w", err)
+	}
+	if q.deleteFieldTermsStmt, err = db.PrepareContext(ctx, deleteFieldTerms); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFieldTerms: %w", err)
+	}
+	if q.deleteLinksStmt, err = db.PrepareContext(ctx, deleteLinks); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteLinks: %w", err)
+	}
+	if q.deletePageStmt, err = db.PrepareContext(ctx, deletePage); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePage: %w", err)
+	}
+	if q.deletePageFieldsStmt, err = db.PrepareContext(ctx, deletePageFields); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePageFields: %w", err)
+	}
+	if q.deletePageTermsStmt, err = db.PrepareContext(ctx, deletePageTerms); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePageTerms: %w", err)
+	}
+	if q.deleteSkippedStmt, err = db.PrepareContext(ctx, deleteSkipped); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSkipped: %w", err)
 	}
 	if q.enqueueStmt, err = db.PrepareContext(ctx, enqueue); err != nil {
 		return nil, fmt.Errorf("error preparing query Enqueue: %w", err)
 	}
+	if q.getAliasTargetStmt, err = db.PrepareContext(ctx, getAliasTarget); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAliasTarget: %w", err)
+	}
+	if q.getAnchorTextStmt, err = db.PrepareContext(ctx, getAnchorText); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAnchorText: %w", err)
+	}
+	if q.getBacklinksStmt, err = db.PrepareContext(ctx, getBacklinks); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBacklinks: %w", err)
+	}
+	if q.getCorpusStatsStmt, err = db.PrepareContext(ctx, getCorpusStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCorpusStats: %w", err)
+	}
+	if q.getCrawlStmt, err = db.PrepareContext(ctx, getCrawl); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCrawl: %w", err)
+	}
+	if q.getCrawlHostPagesStmt, err = db.PrepareContext(ctx, getCrawlHostPages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCrawlHostPages: %w", err)
+	}
+	if q.getCrawlProgressStmt, err = db.PrepareContext(ctx, getCrawlProgress); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCrawlProgress: %w", err)
+	}
+	if q.getDeadLetterStmt, err = db.PrepareContext(ctx, getDeadLetter); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeadLetter: %w", err)
+	}
+	if q.getFieldStatsStmt, err = db.PrepareContext(ctx, getFieldStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFieldStats: %w", err)
+	}
 	if q.getOriginsStmt, err = db.PrepareContext(ctx, getOrigins); err != nil {
 		return nil, fmt.Errorf("error preparing query GetOrigins: %w", err)
 	}
+	if q.getOutlinksStmt, err = db.PrepareContext(ctx, getOutlinks); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOutlinks: %w", err)
+	}
 	if q.getPageStmt, err = db.PrepareContext(ctx, getPage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPage: %w", err)
 	}
+	if q.getPageIDByURLStmt, err = db.PrepareContext(ctx, getPageIDByURL); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPageIDByURL: %w", err)
+	}
+	if q.getPagesForOriginStmt, err = db.PrepareContext(ctx, getPagesForOrigin); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPagesForOrigin: %w", err)
+	}
 	if q.getPagesForTermStmt, err = db.PrepareContext(ctx, getPagesForTerm); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPagesForTerm: %w", err)
 	}
+	if q.getQueuedHostsStmt, err = db.PrepareContext(ctx, getQueuedHosts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetQueuedHosts: %w", err)
+	}
 	if q.getTermStmt, err = db.PrepareContext(ctx, getTerm); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTerm: %w", err)
 	}
+	if q.getValidatorsStmt, err = db.PrepareContext(ctx, getValidators); err != nil {
+		return nil, fmt.Errorf("error preparing query GetValidators: %w", err)
+	}
+	if q.insertAliasStmt, err = db.PrepareContext(ctx, insertAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertAlias: %w", err)
+	}
+	if q.insertCrawlStmt, err = db.PrepareContext(ctx, insertCrawl); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertCrawl: %w", err)
+	}
+	if q.insertDeadLetterStmt, err = db.PrepareContext(ctx, insertDeadLetter); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertDeadLetter: %w", err)
+	}
+	if q.insertLinkStmt, err = db.PrepareContext(ctx, insertLink); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertLink: %w", err)
+	}
 	if q.insertOriginStmt, err = db.PrepareContext(ctx, insertOrigin); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertOrigin: %w", err)
 	}
 	if q.insertPageStmt, err = db.PrepareContext(ctx, insertPage); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertPage: %w", err)
 	}
+	if q.insertPageFieldStmt, err = db.PrepareContext(ctx, insertPageField); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertPageField: %w", err)
+	}
 	if q.insertPageTermStmt, err = db.PrepareContext(ctx, insertPageTerm); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertPageTerm: %w", err)
 	}
+	if q.insertSkippedStmt, err = db.PrepareContext(ctx, insertSkipped); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertSkipped: %w", err)
+	}
+	if q.insertStubPageStmt, err = db.PrepareContext(ctx, insertStubPage); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertStubPage: %w", err)
+	}
 	if q.insertTermStmt, err = db.PrepareContext(ctx, insertTerm); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertTerm: %w", err)
 	}
 	if q.isIndexedStmt, err = db.PrepareContext(ctx, isIndexed); err != nil {
 		return nil, fmt.Errorf("error preparing query IsIndexed: %w", err)
 	}
+	if q.isQueuedStmt, err = db.PrepareContext(ctx, isQueued); err != nil {
+		return nil, fmt.Errorf("error preparing query IsQueued: %w", err)
+	}
+	if q.isSkippedStmt, err = db.PrepareContext(ctx, isSkipped); err != nil {
+		return nil, fmt.Errorf("error preparing query IsSkipped: %w", err)
+	}
+	if q.leaseHostStmt, err = db.PrepareContext(ctx, leaseHost); err != nil {
+		return nil, fmt.Errorf("error preparing query LeaseHost: %w", err)
+	}
+	if q.listDeadLettersStmt, err = db.PrepareContext(ctx, listDeadLetters); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDeadLetters: %w", err)
+	}
+	if q.listFollowLinksStmt, err = db.PrepareContext(ctx, listFollowLinks); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFollowLinks: %w", err)
+	}
+	if q.listLinksStmt, err = db.PrepareContext(ctx, listLinks); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLinks: %w", err)
+	}
+	if q.listPageRanksStmt, err = db.PrepareContext(ctx, listPageRanks); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPageRanks: %w", err)
+	}
+	if q.listPagesStmt, err = db.PrepareContext(ctx, listPages); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPages: %w", err)
+	}
+	if q.moveLinksStmt, err = db.PrepareContext(ctx, moveLinks); err != nil {
+		return nil, fmt.Errorf("error preparing query MoveLinks: %w", err)
+	}
+	if q.moveOriginsStmt, err = db.PrepareContext(ctx, moveOrigins); err != nil {
+		return nil, fmt.Errorf("error preparing query MoveOrigins: %w", err)
+	}
+	if q.releaseLeaseStmt, err = db.PrepareContext(ctx, releaseLease); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseLease: %w", err)
+	}
+	if q.requeueExpiredLeasesStmt, err = db.PrepareContext(ctx, requeueExpiredLeases); err != nil {
+		return nil, fmt.Errorf("error preparing query RequeueExpiredLeases: %w", err)
+	}
+	if q.retargetAliasesStmt, err = db.PrepareContext(ctx, retargetAliases); err != nil {
+		return nil, fmt.Errorf("error preparing query RetargetAliases: %w", err)
+	}
+	if q.retryLeaseStmt, err = db.PrepareContext(ctx, retryLease); err != nil {
+		return nil, fmt.Errorf("error preparing query RetryLease: %w", err)
+	}
+	if q.touchPageStmt, err = db.PrepareContext(ctx, touchPage); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchPage: %w", err)
+	}
+	if q.truncateCrawlStmt, err = db.PrepareContext(ctx, truncateCrawl); err != nil {
+		return nil, fmt.Errorf("error preparing query TruncateCrawl: %w", err)
+	}
 	if q.updatePageStmt, err = db.PrepareContext(ctx, updatePage); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdatePage: %w", err)
 	}
+	if q.updatePageRankStmt, err = db.PrepareContext(ctx, updatePageRank); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdatePageRank: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
-	if q.dequeueStmt != nil {
-		if cerr := q.dequeueStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing dequeueStmt: %w", cerr)
+	if q.ackLeaseStmt != nil {
+		if cerr := q.ackLeaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing ackLeaseStmt: %w", cerr)
+		}
+	}
+	if q.countCrawlHostPageStmt != nil {
+		if cerr := q.countCrawlHostPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countCrawlHostPageStmt: %w", cerr)
+		}
+	}
+	if q.countCrawlPageStmt != nil {
+		if cerr := q.countCrawlPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countCrawlPageStmt: %w", cerr)
+		}
+	}
+	if q.deleteAliasStmt != nil {
+		if cerr := q.deleteAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAliasStmt: %w", cerr)
+		}
+	}
+	if q.deleteDeadLetterStmt != nil {
+		if cerr := q.deleteDeadLetterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDeadLetterStmt: %w", cerr)
+		}
+	}
+	if q.deleteFieldStmt != nil {
+		if cerr := q.deleteFieldStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFieldStmt: %w", cerr)
+		}
+	}
+	if q.deleteFieldTermsStmt != nil {
+		if cerr := q.deleteFieldTermsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFieldTermsStmt: %w", cerr)
+		}
+	}
+	if q.deleteLinksStmt != nil {
+		if cerr := q.deleteLinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteLinksStmt: %w", cerr)
+		}
+	}
+	if q.deletePageStmt != nil {
+		if cerr := q.deletePageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePageStmt: %w", cerr)
+		}
+	}
+	if q.deletePageFieldsStmt != nil {
+		if cerr := q.deletePageFieldsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePageFieldsStmt: %w", cerr)
+		}
+	}
+	if q.deletePageTermsStmt != nil {
+		if cerr := q.deletePageTermsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePageTermsStmt: %w", cerr)
+		}
+	}
+	if q.deleteSkippedStmt != nil {
+		if cerr := q.deleteSkippedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSkippedStmt: %w", cerr)
 		}
 	}
 	if q.enqueueStmt != nil {
@@ -75,26 +277,116 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing enqueueStmt: %w", cerr)
 		}
 	}
+	if q.getAliasTargetStmt != nil {
+		if cerr := q.getAliasTargetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAliasTargetStmt: %w", cerr)
+		}
+	}
+	if q.getAnchorTextStmt != nil {
+		if cerr := q.getAnchorTextStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAnchorTextStmt: %w", cerr)
+		}
+	}
+	if q.getBacklinksStmt != nil {
+		if cerr := q.getBacklinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBacklinksStmt: %w", cerr)
+		}
+	}
+	if q.getCorpusStatsStmt != nil {
+		if cerr := q.getCorpusStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCorpusStatsStmt: %w", cerr)
+		}
+	}
+	if q.getCrawlStmt != nil {
+		if cerr := q.getCrawlStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCrawlStmt: %w", cerr)
+		}
+	}
+	if q.getCrawlHostPagesStmt != nil {
+		if cerr := q.getCrawlHostPagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCrawlHostPagesStmt: %w", cerr)
+		}
+	}
+	if q.getCrawlProgressStmt != nil {
+		if cerr := q.getCrawlProgressStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCrawlProgressStmt: %w", cerr)
+		}
+	}
+	if q.getDeadLetterStmt != nil {
+		if cerr := q.getDeadLetterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeadLetterStmt: %w", cerr)
+		}
+	}
+	if q.getFieldStatsStmt != nil {
+		if cerr := q.getFieldStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFieldStatsStmt: %w", cerr)
+		}
+	}
 	if q.getOriginsStmt != nil {
 		if cerr := q.getOriginsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getOriginsStmt: %w", cerr)
 		}
 	}
+	if q.getOutlinksStmt != nil {
+		if cerr := q.getOutlinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOutlinksStmt: %w", cerr)
+		}
+	}
 	if q.getPageStmt != nil {
 		if cerr := q.getPageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPageStmt: %w", cerr)
 		}
 	}
+	if q.getPageIDByURLStmt != nil {
+		if cerr := q.getPageIDByURLStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPageIDByURLStmt: %w", cerr)
+		}
+	}
+	if q.getPagesForOriginStmt != nil {
+		if cerr := q.getPagesForOriginStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPagesForOriginStmt: %w", cerr)
+		}
+	}
 	if q.getPagesForTermStmt != nil {
 		if cerr := q.getPagesForTermStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPagesForTermStmt: %w", cerr)
 		}
 	}
+	if q.getQueuedHostsStmt != nil {
+		if cerr := q.getQueuedHostsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getQueuedHostsStmt: %w", cerr)
+		}
+	}
 	if q.getTermStmt != nil {
 		if cerr := q.getTermStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTermStmt: %w", cerr)
 		}
 	}
+	if q.getValidatorsStmt != nil {
+		if cerr := q.getValidatorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getValidatorsStmt: %w", cerr)
+		}
+	}
+	if q.insertAliasStmt != nil {
+		if cerr := q.insertAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertAliasStmt: %w", cerr)
+		}
+	}
+	if q.insertCrawlStmt != nil {
+		if cerr := q.insertCrawlStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertCrawlStmt: %w", cerr)
+		}
+	}
+	if q.insertDeadLetterStmt != nil {
+		if cerr := q.insertDeadLetterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertDeadLetterStmt: %w", cerr)
+		}
+	}
+	if q.insertLinkStmt != nil {
+		if cerr := q.insertLinkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertLinkStmt: %w", cerr)
+		}
+	}
 	if q.insertOriginStmt != nil {
 		if cerr := q.insertOriginStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertOriginStmt: %w", cerr)
@@ -105,11 +397,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing insertPageStmt: %w", cerr)
 		}
 	}
+	if q.insertPageFieldStmt != nil {
+		if cerr := q.insertPageFieldStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertPageFieldStmt: %w", cerr)
+		}
+	}
 	if q.insertPageTermStmt != nil {
 		if cerr := q.insertPageTermStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertPageTermStmt: %w", cerr)
 		}
 	}
+	if q.insertSkippedStmt != nil {
+		if cerr := q.insertSkippedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertSkippedStmt: %w", cerr)
+		}
+	}
+	if q.insertStubPageStmt != nil {
+		if cerr := q.insertStubPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertStubPageStmt: %w", cerr)
+		}
+	}
 	if q.insertTermStmt != nil {
 		if cerr := q.insertTermStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertTermStmt: %w", cerr)
@@ -120,11 +427,96 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing isIndexedStmt: %w", cerr)
 		}
 	}
+	if q.isQueuedStmt != nil {
+		if cerr := q.isQueuedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing isQueuedStmt: %w", cerr)
+		}
+	}
+	if q.isSkippedStmt != nil {
+		if cerr := q.isSkippedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing isSkippedStmt: %w", cerr)
+		}
+	}
+	if q.leaseHostStmt != nil {
+		if cerr := q.leaseHostStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing leaseHostStmt: %w", cerr)
+		}
+	}
+	if q.listDeadLettersStmt != nil {
+		if cerr := q.listDeadLettersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDeadLettersStmt: %w", cerr)
+		}
+	}
+	if q.listFollowLinksStmt != nil {
+		if cerr := q.listFollowLinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFollowLinksStmt: %w", cerr)
+		}
+	}
+	if q.listLinksStmt != nil {
+		if cerr := q.listLinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLinksStmt: %w", cerr)
+		}
+	}
+	if q.listPageRanksStmt != nil {
+		if cerr := q.listPageRanksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPageRanksStmt: %w", cerr)
+		}
+	}
+	if q.listPagesStmt != nil {
+		if cerr := q.listPagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPagesStmt: %w", cerr)
+		}
+	}
+	if q.moveLinksStmt != nil {
+		if cerr := q.moveLinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing moveLinksStmt: %w", cerr)
+		}
+	}
+	if q.moveOriginsStmt != nil {
+		if cerr := q.moveOriginsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing moveOriginsStmt: %w", cerr)
+		}
+	}
+	if q.releaseLeaseStmt != nil {
+		if cerr := q.releaseLeaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseLeaseStmt: %w", cerr)
+		}
+	}
+	if q.requeueExpiredLeasesStmt != nil {
+		if cerr := q.requeueExpiredLeasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing requeueExpiredLeasesStmt: %w", cerr)
+		}
+	}
+	if q.retargetAliasesStmt != nil {
+		if cerr := q.retargetAliasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing retargetAliasesStmt: %w", cerr)
+		}
+	}
+	if q.retryLeaseStmt != nil {
+		if cerr := q.retryLeaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing retryLeaseStmt: %w", cerr)
+		}
+	}
+	if q.touchPageStmt != nil {
+		if cerr := q.touchPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchPageStmt: %w", cerr)
+		}
+	}
+	if q.truncateCrawlStmt != nil {
+		if cerr := q.truncateCrawlStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing truncateCrawlStmt: %w", cerr)
+		}
+	}
 	if q.updatePageStmt != nil {
 		if cerr := q.updatePageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updatePageStmt: %w", cerr)
 		}
 	}
+	if q.updatePageRankStmt != nil {
+		if cerr := q.updatePageRankStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updatePageRankStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -162,37 +554,135 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                  DBTX
-	tx                  *sql.Tx
-	dequeueStmt         *sql.Stmt
-	enqueueStmt         *sql.Stmt
-	getOriginsStmt      *sql.Stmt
-	getPageStmt         *sql.Stmt
-	getPagesForTermStmt *sql.Stmt
-	getTermStmt         *sql.Stmt
-	insertOriginStmt    *sql.Stmt
-	insertPageStmt      *sql.Stmt
-	insertPageTermStmt  *sql.Stmt
-	insertTermStmt      *sql.Stmt
-	isIndexedStmt       *sql.Stmt
-	updatePageStmt      *sql.Stmt
+	db                       DBTX
+	tx                       *sql.Tx
+	ackLeaseStmt             *sql.Stmt
+	countCrawlHostPageStmt   *sql.Stmt
+	countCrawlPageStmt       *sql.Stmt
+	deleteAliasStmt          *sql.Stmt
+	deleteDeadLetterStmt     *sql.Stmt
+	deleteFieldStmt          *sql.Stmt
+	deleteFieldTermsStmt     *sql.Stmt
+	deleteLinksStmt          *sql.Stmt
+	deletePageStmt           *sql.Stmt
+	deletePageFieldsStmt     *sql.Stmt
+	deletePageTermsStmt      *sql.Stmt
+	deleteSkippedStmt        *sql.Stmt
+	enqueueStmt              *sql.Stmt
+	getAliasTargetStmt       *sql.Stmt
+	getAnchorTextStmt        *sql.Stmt
+	getBacklinksStmt         *sql.Stmt
+	getCorpusStatsStmt       *sql.Stmt
+	getCrawlStmt             *sql.Stmt
+	getCrawlHostPagesStmt    *sql.Stmt
+	getCrawlProgressStmt     *sql.Stmt
+	getDeadLetterStmt        *sql.Stmt
+	getFieldStatsStmt        *sql.Stmt
+	getOriginsStmt           *sql.Stmt
+	getOutlinksStmt          *sql.Stmt
+	getPageStmt              *sql.Stmt
+	getPageIDByURLStmt       *sql.Stmt
+	getPagesForOriginStmt    *sql.Stmt
+	getPagesForTermStmt      *sql.Stmt
+	getQueuedHostsStmt       *sql.Stmt
+	getTermStmt              *sql.Stmt
+	getValidatorsStmt        *sql.Stmt
+	insertAliasStmt          *sql.Stmt
+	insertCrawlStmt          *sql.Stmt
+	insertDeadLetterStmt     *sql.Stmt
+	insertLinkStmt           *sql.Stmt
+	insertOriginStmt         *sql.Stmt
+	insertPageStmt           *sql.Stmt
+	insertPageFieldStmt      *sql.Stmt
+	insertPageTermStmt       *sql.Stmt
+	insertSkippedStmt        *sql.Stmt
+	insertStubPageStmt       *sql.Stmt
+	insertTermStmt           *sql.Stmt
+	isIndexedStmt            *sql.Stmt
+	isQueuedStmt             *sql.Stmt
+	isSkippedStmt            *sql.Stmt
+	leaseHostStmt            *sql.Stmt
+	listDeadLettersStmt      *sql.Stmt
+	listFollowLinksStmt      *sql.Stmt
+	listLinksStmt            *sql.Stmt
+	listPageRanksStmt        *sql.Stmt
+	listPagesStmt            *sql.Stmt
+	moveLinksStmt            *sql.Stmt
+	moveOriginsStmt          *sql.Stmt
+	releaseLeaseStmt         *sql.Stmt
+	requeueExpiredLeasesStmt *sql.Stmt
+	retargetAliasesStmt      *sql.Stmt
+	retryLeaseStmt           *sql.Stmt
+	touchPageStmt            *sql.Stmt
+	truncateCrawlStmt        *sql.Stmt
+	updatePageStmt           *sql.Stmt
+	updatePageRankStmt       *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                  tx,
-		tx:                  tx,
-		dequeueStmt:         q.dequeueStmt,
-		enqueueStmt:         q.enqueueStmt,
-		getOriginsStmt:      q.getOriginsStmt,
-		getPageStmt:         q.getPageStmt,
-		getPagesForTermStmt: q.getPagesForTermStmt,
-		getTermStmt:         q.getTermStmt,
-		insertOriginStmt:    q.insertOriginStmt,
-		insertPageStmt:      q.insertPageStmt,
-		insertPageTermStmt:  q.insertPageTermStmt,
-		insertTermStmt:      q.insertTermStmt,
-		isIndexedStmt:       q.isIndexedStmt,
-		updatePageStmt:      q.updatePageStmt,
+		db:                       tx,
+		tx:                       tx,
+		ackLeaseStmt:             q.ackLeaseStmt,
+		countCrawlHostPageStmt:   q.countCrawlHostPageStmt,
+		countCrawlPageStmt:       q.countCrawlPageStmt,
+		deleteAliasStmt:          q.deleteAliasStmt,
+		deleteDeadLetterStmt:     q.deleteDeadLetterStmt,
+		deleteFieldStmt:          q.deleteFieldStmt,
+		deleteFieldTermsStmt:     q.deleteFieldTermsStmt,
+		deleteLinksStmt:          q.deleteLinksStmt,
+		deletePageStmt:           q.deletePageStmt,
+		deletePageFieldsStmt:     q.deletePageFieldsStmt,
+		deletePageTermsStmt:      q.deletePageTermsStmt,
+		deleteSkippedStmt:        q.deleteSkippedStmt,
+		enqueueStmt:              q.enqueueStmt,
+		getAliasTargetStmt:       q.getAliasTargetStmt,
+		getAnchorTextStmt:        q.getAnchorTextStmt,
+		getBacklinksStmt:         q.getBacklinksStmt,
+		getCorpusStatsStmt:       q.getCorpusStatsStmt,
+		getCrawlStmt:             q.getCrawlStmt,
+		getCrawlHostPagesStmt:    q.getCrawlHostPagesStmt,
+		getCrawlProgressStmt:     q.getCrawlProgressStmt,
+		getDeadLetterStmt:        q.getDeadLetterStmt,
+		getFieldStatsStmt:        q.getFieldStatsStmt,
+		getOriginsStmt:           q.getOriginsStmt,
+		getOutlinksStmt:          q.getOutlinksStmt,
+		getPageStmt:              q.getPageStmt,
+		getPageIDByURLStmt:       q.getPageIDByURLStmt,
+		getPagesForOriginStmt:    q.getPagesForOriginStmt,
+		getPagesForTermStmt:      q.getPagesForTermStmt,
+		getQueuedHostsStmt:       q.getQueuedHostsStmt,
+		getTermStmt:              q.getTermStmt,
+		getValidatorsStmt:        q.getValidatorsStmt,
+		insertAliasStmt:          q.insertAliasStmt,
+		insertCrawlStmt:          q.insertCrawlStmt,
+		insertDeadLetterStmt:     q.insertDeadLetterStmt,
+		insertLinkStmt:           q.insertLinkStmt,
+		insertOriginStmt:         q.insertOriginStmt,
+		insertPageStmt:           q.insertPageStmt,
+		insertPageFieldStmt:      q.insertPageFieldStmt,
+		insertPageTermStmt:       q.insertPageTermStmt,
+		insertSkippedStmt:        q.insertSkippedStmt,
+		insertStubPageStmt:       q.insertStubPageStmt,
+		insertTermStmt:           q.insertTermStmt,
+		isIndexedStmt:            q.isIndexedStmt,
+		isQueuedStmt:             q.isQueuedStmt,
+		isSkippedStmt:            q.isSkippedStmt,
+		leaseHostStmt:            q.leaseHostStmt,
+		listDeadLettersStmt:      q.listDeadLettersStmt,
+		listFollowLinksStmt:      q.listFollowLinksStmt,
+		listLinksStmt:            q.listLinksStmt,
+		listPageRanksStmt:        q.listPageRanksStmt,
+		listPagesStmt:            q.listPagesStmt,
+		moveLinksStmt:            q.moveLinksStmt,
+		moveOriginsStmt:          q.moveOriginsStmt,
+		releaseLeaseStmt:         q.releaseLeaseStmt,
+		requeueExpiredLeasesStmt: q.requeueExpiredLeasesStmt,
+		retargetAliasesStmt:      q.retargetAliasesStmt,
+		retryLeaseStmt:           q.retryLeaseStmt,
+		touchPageStmt:            q.touchPageStmt,
+		truncateCrawlStmt:        q.truncateCrawlStmt,
+		updatePageStmt:           q.updatePageStmt,
+		updatePageRankStmt:       q.updatePageRankStmt,
 	}
 }
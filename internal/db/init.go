@@ -51,7 +51,12 @@ func Init(ctx context.Context, file string, callback UpdateHook) (*DB, error) {
 		return nil, err
 	}
 
-	// create tables
+	// bring tables created by an earlier version up to date, then create
+	// those that don't exist yet
+	if err = migrate(ctx, db); err != nil {
+		return nil, err
+	}
+
 	if _, err = db.ExecContext(ctx, Schema); err != nil {
 		return nil, err
 	}
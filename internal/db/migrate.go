@@ -0,0 +1,333 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/url"
+)
+
+// migration changes the schema of a database created by an earlier version.
+// tables that it introduces are created as they were at the time, so that the
+// migrations that follow can change them.
+type migration func(ctx context.Context, tx *sql.Tx) error
+
+// migrations are applied in order to bring a database up to date, each schema
+// change appends one. the user_version pragma of the database is the number
+// that have been applied.
+var migrations = []migration{
+	queueHost,
+	queueLeases,
+	queueRetries,
+	pageLength,
+	termPositions,
+	termFields,
+	pageText,
+	pageFields,
+	anchors,
+	linkGraph,
+	aliases,
+	validators,
+	skipped,
+	pageTruncated,
+	crawls,
+	crawlBudgets,
+}
+
+// migrate applies the migrations the database hasn't had yet, each in its own
+// transaction. it runs before Schema, whose indexes may depend on the columns
+// they add. a new database is created by Schema as it is now, so none of them
+// apply to it.
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("error getting schema version: %w", err)
+	}
+
+	if version == 0 {
+		var tables int
+		err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'queue'").Scan(&tables)
+		if err != nil {
+			return fmt.Errorf("error checking for tables: %w", err)
+		}
+
+		if tables == 0 {
+			return setVersion(ctx, db, len(migrations))
+		}
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if err = migrations[i](ctx, tx); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error applying migration %d: %w", i+1, err)
+		}
+
+		if err = setVersion(ctx, tx, i+1); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+
+		slog.Info("migrated database", "version", i+1)
+	}
+
+	return nil
+}
+
+// setVersion records the number of migrations that have been applied
+func setVersion(ctx context.Context, db DBTX, version int) error {
+	// pragmas can't be parameterized
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+		return fmt.Errorf("error setting schema version: %w", err)
+	}
+	return nil
+}
+
+// exec runs each of the statements of a migration in order
+func exec(ctx context.Context, tx *sql.Tx, stmts ...string) error {
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("error executing %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// queueHost adds the host of queued urls, which the queue is scheduled by, and
+// fills it in for those already queued
+func queueHost(ctx context.Context, tx *sql.Tx) error {
+	if err := exec(ctx, tx, "ALTER TABLE queue ADD COLUMN host TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, url FROM queue")
+	if err != nil {
+		return fmt.Errorf("error listing queue: %w", err)
+	}
+
+	hosts := map[int64]string{}
+	for rows.Next() {
+		var (
+			id  int64
+			raw string
+		)
+		if err = rows.Scan(&id, &raw); err != nil {
+			rows.Close()
+			return err
+		}
+		if u, err := url.Parse(raw); err == nil {
+			hosts[id] = u.Host
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for id, host := range hosts {
+		if _, err = tx.ExecContext(ctx, "UPDATE queue SET host = ? WHERE id = ?", host, id); err != nil {
+			return fmt.Errorf("error setting queue host: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// queueLeases adds the lease of queued urls
+func queueLeases(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE queue ADD COLUMN lease_expires_at TIMESTAMP",
+		"ALTER TABLE queue ADD COLUMN worker_id INTEGER",
+	)
+}
+
+// queueRetries adds the retry state of queued urls and the dead-letter table.
+// columns can only be added with a constant default, so not_before defaults to
+// the epoch rather than the current time.
+func queueRetries(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE queue ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0",
+		"ALTER TABLE queue ADD COLUMN not_before TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00'",
+		"ALTER TABLE queue ADD COLUMN last_error TEXT NOT NULL DEFAULT ''",
+		`CREATE TABLE IF NOT EXISTS dead_letters (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    origin TEXT NOT NULL,
+    depth INTEGER NOT NULL,
+    max_depth INTEGER NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL
+)`,
+	)
+}
+
+// pageLength adds the length of pages. pages that were already indexed have a
+// length of 0 until they are reindexed.
+func pageLength(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx, "ALTER TABLE pages ADD COLUMN length INTEGER NOT NULL DEFAULT 0")
+}
+
+// termPositions adds the positions of terms in pages. postings that were
+// already indexed have none until their page is reindexed.
+func termPositions(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx, "ALTER TABLE page_terms ADD COLUMN positions BLOB NOT NULL DEFAULT x''")
+}
+
+// termFields adds the title of pages and rebuilds page_terms, as a term can now
+// occur in a page once per field rather than once. every existing posting is
+// from the body.
+func termFields(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE pages ADD COLUMN title TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE page_terms RENAME TO page_terms_old",
+		`CREATE TABLE page_terms (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    page_id INTEGER NOT NULL,
+    term_id INTEGER NOT NULL,
+    count INTEGER NOT NULL DEFAULT 1,
+    positions BLOB NOT NULL DEFAULT x'',
+    field TEXT NOT NULL DEFAULT 'body',
+    FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    UNIQUE (page_id, term_id, field)
+)`,
+		`INSERT INTO page_terms (id, created_at, page_id, term_id, count, positions)
+SELECT id, created_at, page_id, term_id, count, positions FROM page_terms_old`,
+		"DROP TABLE page_terms_old",
+	)
+}
+
+// pageText adds the text of pages that snippets are made from
+func pageText(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx, "ALTER TABLE pages ADD COLUMN text BLOB NOT NULL DEFAULT x''")
+}
+
+// pageFields adds the description and headings of pages and the length of each
+// of their fields
+func pageFields(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE pages ADD COLUMN description TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE pages ADD COLUMN headings TEXT NOT NULL DEFAULT ''",
+		`CREATE TABLE IF NOT EXISTS page_fields (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    page_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    length INTEGER NOT NULL,
+    FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    UNIQUE (page_id, field)
+)`,
+	)
+}
+
+// anchors adds stub pages and the anchor text of the links to them
+func anchors(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE pages ADD COLUMN stub BOOLEAN NOT NULL DEFAULT FALSE",
+		`CREATE TABLE IF NOT EXISTS anchors (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    source_page_id INTEGER NOT NULL,
+    target_page_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    FOREIGN KEY (source_page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    FOREIGN KEY (target_page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    UNIQUE (source_page_id, target_page_id, text)
+)`,
+	)
+}
+
+// linkGraph adds the pagerank of pages and turns anchors into links, the edges
+// of the link graph. the anchors already recorded are kept as links.
+func linkGraph(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE pages ADD COLUMN pagerank REAL NOT NULL DEFAULT 0",
+		"DROP INDEX IF EXISTS anchors_target_idx",
+		"ALTER TABLE anchors RENAME TO links",
+		"ALTER TABLE links RENAME COLUMN text TO anchor_text",
+	)
+}
+
+// aliases adds the urls that redirect to the url a page is indexed under
+func aliases(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx, `CREATE TABLE IF NOT EXISTS aliases (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    target_url TEXT NOT NULL
+)`)
+}
+
+// validators adds the validators of pages and nofollow links
+func validators(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE pages ADD COLUMN etag TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE pages ADD COLUMN last_modified TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE links ADD COLUMN nofollow BOOLEAN NOT NULL DEFAULT FALSE",
+	)
+}
+
+// skipped adds the urls that were fetched but not indexed
+func skipped(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx, `CREATE TABLE IF NOT EXISTS skipped (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    reason TEXT NOT NULL
+)`)
+}
+
+// pageTruncated marks pages whose body was longer than the maximum that is read
+func pageTruncated(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx, "ALTER TABLE pages ADD COLUMN truncated BOOLEAN NOT NULL DEFAULT FALSE")
+}
+
+// crawls adds the crawls that urls are queued for. urls that were already
+// queued aren't part of one.
+func crawls(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE queue ADD COLUMN crawl_id INTEGER NOT NULL DEFAULT 0",
+		"ALTER TABLE dead_letters ADD COLUMN crawl_id INTEGER NOT NULL DEFAULT 0",
+		`CREATE TABLE IF NOT EXISTS crawls (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    origin TEXT NOT NULL,
+    max_depth INTEGER NOT NULL,
+    scope_mode INTEGER NOT NULL DEFAULT 0,
+    include_patterns TEXT NOT NULL DEFAULT '[]',
+    exclude_patterns TEXT NOT NULL DEFAULT '[]'
+)`,
+	)
+}
+
+// crawlBudgets adds the budgets of crawls and the pages queued for them, in
+// total and by host
+func crawlBudgets(ctx context.Context, tx *sql.Tx) error {
+	return exec(ctx, tx,
+		"ALTER TABLE crawls ADD COLUMN max_pages INTEGER NOT NULL DEFAULT 0",
+		"ALTER TABLE crawls ADD COLUMN max_pages_per_host INTEGER NOT NULL DEFAULT 0",
+		"ALTER TABLE crawls ADD COLUMN deadline TIMESTAMP",
+		"ALTER TABLE crawls ADD COLUMN pages INTEGER NOT NULL DEFAULT 0",
+		"ALTER TABLE crawls ADD COLUMN truncated BOOLEAN NOT NULL DEFAULT FALSE",
+		`CREATE TABLE IF NOT EXISTS crawl_hosts (
+    id INTEGER PRIMARY KEY,
+    crawl_id INTEGER NOT NULL,
+    host TEXT NOT NULL,
+    pages INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (crawl_id) REFERENCES crawls (id) ON DELETE CASCADE,
+    UNIQUE (crawl_id, host)
+)`,
+	)
+}
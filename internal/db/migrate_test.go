@@ -0,0 +1,227 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// baseline is the schema from before it was versioned
+const baseline = `
+CREATE TABLE queue (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    origin TEXT NOT NULL,
+    depth INTEGER NOT NULL,
+    max_depth INTEGER NOT NULL
+);
+
+CREATE TABLE pages (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    depth INTEGER NOT NULL
+);
+
+CREATE TABLE origins (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    page_id INTEGER NOT NULL,
+    origin TEXT NOT NULL,
+    FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    UNIQUE (page_id, origin)
+);
+
+CREATE TABLE terms (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    term TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE page_terms (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    page_id INTEGER NOT NULL,
+    term_id INTEGER NOT NULL,
+    count INTEGER NOT NULL DEFAULT 1,
+    FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    UNIQUE (page_id, term_id)
+);
+
+INSERT INTO queue (url, origin, depth, max_depth) VALUES ('https://example.com:8080/a', 'https://example.com/', 1, 2);
+INSERT INTO pages (url, depth) VALUES ('https://example.com/', 0);
+INSERT INTO pages (url, depth) VALUES ('https://example.com/b', 1);
+INSERT INTO terms (term) VALUES ('example');
+INSERT INTO page_terms (page_id, term_id, count) VALUES (1, 1, 3);
+`
+
+// newBaseline returns the file of a database created with the baseline schema
+func newBaseline(t *testing.T) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "test.db")
+
+	old, err := sql.Open("sqlite3", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer old.Close()
+
+	if _, err = old.Exec(baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func initDB(t *testing.T, file string) *DB {
+	t.Helper()
+
+	d, err := Init(context.Background(), file, func(int, string, string, int64) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SQL.Close() })
+
+	return d
+}
+
+// schema returns the columns of every table, in order of name
+func schema(t *testing.T, d *DB) map[string][]string {
+	t.Helper()
+
+	rows, err := d.SQL.Query(`
+SELECT m.name, c.name, c.type
+FROM sqlite_master AS m
+JOIN pragma_table_info(m.name) AS c
+WHERE m.type = 'table'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	ret := map[string][]string{}
+	for rows.Next() {
+		var table, column, typ string
+		if err = rows.Scan(&table, &column, &typ); err != nil {
+			t.Fatal(err)
+		}
+		ret[table] = append(ret[table], column+" "+typ)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, columns := range ret {
+		slices.Sort(columns)
+	}
+
+	return ret
+}
+
+func version(t *testing.T, d *DB) int {
+	t.Helper()
+
+	var v int
+	if err := d.SQL.QueryRow("PRAGMA user_version").Scan(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestMigrate(t *testing.T) {
+	// every statement is prepared against the migrated tables
+	file := newBaseline(t)
+	d := initDB(t, file)
+
+	if v := version(t, d); v != len(migrations) {
+		t.Errorf("got version %d, want %d", v, len(migrations))
+	}
+
+	fresh := initDB(t, filepath.Join(t.TempDir(), "fresh.db"))
+	if v := version(t, fresh); v != len(migrations) {
+		t.Errorf("got version %d for a new database, want %d", v, len(migrations))
+	}
+
+	// the migrated tables are the same as those of a new database, apart from
+	// the order of their columns
+	got, want := schema(t, d), schema(t, fresh)
+	for table, columns := range want {
+		if !slices.Equal(got[table], columns) {
+			t.Errorf("got %s columns %v, want %v", table, got[table], columns)
+		}
+	}
+	for table := range got {
+		if _, ok := want[table]; !ok {
+			t.Errorf("got table %s, which a new database doesn't have", table)
+		}
+	}
+
+	var host string
+	if err := d.SQL.QueryRow("SELECT host FROM queue").Scan(&host); err != nil {
+		t.Fatal(err)
+	}
+	if host != "example.com:8080" {
+		t.Errorf("got queued host %q, want %q", host, "example.com:8080")
+	}
+
+	var (
+		field string
+		count int64
+	)
+	if err := d.SQL.QueryRow("SELECT field, count FROM page_terms").Scan(&field, &count); err != nil {
+		t.Fatal(err)
+	}
+	if field != "body" || count != 3 {
+		t.Errorf("got posting %s %d, want body 3", field, count)
+	}
+
+	// a term can now be indexed once per field
+	if _, err := d.SQL.Exec("INSERT INTO page_terms (page_id, term_id, field) VALUES (1, 1, 'title')"); err != nil {
+		t.Error(err)
+	}
+
+	// migrating again is a no-op
+	d.SQL.Close()
+	initDB(t, file)
+}
+
+func TestMigrateAnchors(t *testing.T) {
+	file := newBaseline(t)
+
+	old, err := sql.Open("sqlite3", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer old.Close()
+
+	// bring the database up to when anchor text was first indexed, which was
+	// the 9th change to the schema
+	all := migrations
+	migrations = all[:9]
+	err = migrate(context.Background(), old)
+	migrations = all
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = old.Exec("INSERT INTO anchors (source_page_id, target_page_id, text) VALUES (1, 2, 'example')"); err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	d := initDB(t, file)
+
+	var text string
+	if err = d.SQL.QueryRow("SELECT anchor_text FROM links WHERE source_page_id = 1 AND target_page_id = 2").Scan(&text); err != nil {
+		t.Fatal(err)
+	}
+	if text != "example" {
+		t.Errorf("got anchor text %q, want %q", text, "example")
+	}
+}
@@ -5,9 +5,60 @@
 package db
 
 import (
+	"database/sql"
 	"time"
 )
 
+type Alias struct {
+	ID        int64
+	CreatedAt time.Time
+	URL       string
+	TargetURL string
+}
+
+type Crawl struct {
+	ID              int64
+	CreatedAt       time.Time
+	Origin          string
+	MaxDepth        int64
+	ScopeMode       int64
+	IncludePatterns string
+	ExcludePatterns string
+	MaxPages        int64
+	MaxPagesPerHost int64
+	Deadline        sql.NullTime
+	Pages           int64
+	Truncated       bool
+}
+
+type CrawlHost struct {
+	ID      int64
+	CrawlID int64
+	Host    string
+	Pages   int64
+}
+
+type DeadLetter struct {
+	ID        int64
+	CreatedAt time.Time
+	URL       string
+	Origin    string
+	Depth     int64
+	MaxDepth  int64
+	Attempts  int64
+	LastError string
+	CrawlID   int64
+}
+
+type Link struct {
+	ID           int64
+	CreatedAt    time.Time
+	SourcePageID int64
+	TargetPageID int64
+	AnchorText   string
+	Nofollow     bool
+}
+
 type Origin struct {
 	ID        int64
 	CreatedAt time.Time
@@ -16,11 +67,29 @@ type Origin struct {
 }
 
 type Page struct {
-	ID         int64
-	CreatedAt  time.Time
-	ModifiedAt time.Time
-	URL        string
-	Depth      int64
+	ID           int64
+	CreatedAt    time.Time
+	ModifiedAt   time.Time
+	URL          string
+	Depth        int64
+	Length       int64
+	Title        string
+	Text         []byte
+	Description  string
+	Headings     string
+	Stub         bool
+	Pagerank     float64
+	Etag         string
+	LastModified string
+	Truncated    bool
+}
+
+type PageField struct {
+	ID        int64
+	CreatedAt time.Time
+	PageID    int64
+	Field     string
+	Length    int64
 }
 
 type PageTerm struct {
@@ -29,15 +98,32 @@ type PageTerm struct {
 	PageID    int64
 	TermID    int64
 	Count     int64
+	Positions []byte
+	Field     string
 }
 
 type Queue struct {
-	ID        int64
-	CreatedAt time.Time
-	URL       string
-	Origin    string
-	Depth     int64
-	MaxDepth  int64
+	ID             int64
+	CreatedAt      time.Time
+	URL            string
+	Host           string
+	Origin         string
+	Depth          int64
+	MaxDepth       int64
+	LeaseExpiresAt sql.NullTime
+	WorkerID       sql.NullInt64
+	Attempts       int64
+	NotBefore      time.Time
+	LastError      string
+	CrawlID        int64
+}
+
+type Skipped struct {
+	ID          int64
+	CreatedAt   time.Time
+	URL         string
+	ContentType string
+	Reason      string
 }
 
 type Term struct {
@@ -1,40 +1,200 @@
--- name: Enqueue :exec
+-- name: Enqueue :execrows
 INSERT INTO queue (
     url,
+    host,
     origin,
     depth,
-    max_depth
+    max_depth,
+    crawl_id
 ) VALUES (
+    ?,
+    ?,
     ?,
     ?,
     ?,
     ?
 ) ON CONFLICT (url) DO NOTHING;
 
--- name: Dequeue :one
-DELETE FROM queue WHERE id = (
-    SELECT id FROM queue ORDER BY id ASC LIMIT 1
+-- name: GetQueuedHosts :many
+SELECT host
+FROM queue
+WHERE lease_expires_at IS NULL AND not_before <= ?
+GROUP BY host
+ORDER BY MIN(id) ASC
+LIMIT ?;
+
+-- name: LeaseHost :one
+UPDATE queue SET lease_expires_at = ?, worker_id = ? WHERE id = (
+    SELECT id
+    FROM queue
+    WHERE host = ? AND lease_expires_at IS NULL AND not_before <= ?
+    ORDER BY id ASC
+    LIMIT 1
+) RETURNING *;
+
+-- name: AckLease :exec
+DELETE FROM queue WHERE id = ? AND worker_id = ?;
+
+-- name: ReleaseLease :exec
+UPDATE queue SET lease_expires_at = NULL, worker_id = NULL WHERE id = ? AND worker_id = ?;
+
+-- name: RetryLease :exec
+UPDATE queue
+SET
+    lease_expires_at = NULL,
+    worker_id = NULL,
+    attempts = attempts + 1,
+    not_before = ?,
+    last_error = ?
+WHERE id = ? AND worker_id = ?;
+
+-- name: RequeueExpiredLeases :execrows
+UPDATE queue SET lease_expires_at = NULL, worker_id = NULL WHERE lease_expires_at < ?;
+
+-- name: InsertDeadLetter :exec
+INSERT INTO dead_letters (
+    url,
+    origin,
+    depth,
+    max_depth,
+    attempts,
+    last_error,
+    crawl_id
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (url) DO UPDATE
+SET
+    created_at = CURRENT_TIMESTAMP,
+    attempts = excluded.attempts,
+    last_error = excluded.last_error;
+
+-- name: InsertCrawl :one
+INSERT INTO crawls (
+    origin,
+    max_depth,
+    scope_mode,
+    include_patterns,
+    exclude_patterns,
+    max_pages,
+    max_pages_per_host,
+    deadline
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
 ) RETURNING *;
 
+-- name: GetCrawl :one
+SELECT * FROM crawls WHERE id = ?;
+
+-- name: GetCrawlHostPages :one
+SELECT pages FROM crawl_hosts WHERE crawl_id = ? AND host = ?;
+
+-- name: GetCrawlProgress :one
+SELECT
+    (SELECT COUNT(*) FROM queue WHERE queue.crawl_id = sqlc.arg(crawl_id)) AS queued,
+    (SELECT COUNT(*) FROM dead_letters WHERE dead_letters.crawl_id = sqlc.arg(crawl_id)) AS dead_letters;
+
+-- name: CountCrawlPage :exec
+UPDATE crawls SET pages = pages + 1 WHERE id = ?;
+
+-- name: CountCrawlHostPage :exec
+INSERT INTO crawl_hosts (
+    crawl_id,
+    host,
+    pages
+) VALUES (
+    ?,
+    ?,
+    1
+) ON CONFLICT (crawl_id, host) DO UPDATE SET
+    pages = pages + 1;
+
+-- name: TruncateCrawl :exec
+UPDATE crawls SET truncated = TRUE WHERE id = ?;
+
+-- name: IsQueued :one
+SELECT EXISTS(SELECT 1 FROM queue WHERE url = ?);
+
+-- name: ListDeadLetters :many
+SELECT * FROM dead_letters ORDER BY id ASC;
+
+-- name: GetDeadLetter :one
+SELECT * FROM dead_letters WHERE url = ?;
+
+-- name: DeleteDeadLetter :exec
+DELETE FROM dead_letters WHERE id = ?;
+
 -- name: IsIndexed :one
 SELECT *
 FROM pages
 WHERE
-    url = ?
-    AND modified_at >= ?;
+    url = COALESCE((SELECT target_url FROM aliases WHERE aliases.url = sqlc.arg(url)), sqlc.arg(url))
+    AND modified_at >= sqlc.arg(modified_at)
+    AND NOT stub;
+
+-- name: IsSkipped :one
+SELECT EXISTS(
+    SELECT 1
+    FROM skipped
+    WHERE
+        url = COALESCE((SELECT target_url FROM aliases WHERE aliases.url = sqlc.arg(url)), sqlc.arg(url))
+        AND created_at >= sqlc.arg(created_at)
+);
 
 -- name: InsertPage :one
 INSERT INTO pages (
     url,
-    depth
+    depth,
+    length,
+    title,
+    text,
+    description,
+    headings,
+    etag,
+    last_modified,
+    truncated
 ) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
     ?,
     ?
 ) ON CONFLICT (url) DO NOTHING
 RETURNING *;
 
 -- name: UpdatePage :one
-UPDATE pages SET depth = ?, modified_at = CURRENT_TIMESTAMP WHERE url = ? RETURNING *;
+UPDATE pages SET depth = ?, length = ?, title = ?, text = ?, description = ?, headings = ?, etag = ?, last_modified = ?, truncated = ?, stub = FALSE, modified_at = CURRENT_TIMESTAMP WHERE url = ? RETURNING *;
+
+-- name: TouchPage :exec
+UPDATE pages SET modified_at = CURRENT_TIMESTAMP WHERE url = ?;
+
+-- name: GetValidators :one
+SELECT etag, last_modified FROM pages WHERE url = ? AND NOT stub;
+
+-- name: ListFollowLinks :many
+SELECT DISTINCT t.url
+FROM links AS l
+JOIN pages AS s on s.id = l.source_page_id
+JOIN pages AS t on t.id = l.target_page_id
+WHERE s.url = ? AND NOT l.nofollow
+ORDER BY l.id;
 
 -- name: InsertOrigin :exec
 INSERT INTO origins (
@@ -59,25 +219,200 @@ SELECT * FROM terms WHERE term = ?;
 -- name: InsertPageTerm :exec
 INSERT INTO page_terms (
     page_id,
-    term_id
+    term_id,
+    count,
+    positions,
+    field
 ) VALUES (
+    ?,
+    ?,
+    ?,
     ?,
     ?
-) ON CONFLICT (page_id, term_id) DO UPDATE
-SET count = page_terms.count + 1;
+) ON CONFLICT (page_id, term_id, field) DO UPDATE
+SET count = excluded.count, positions = excluded.positions;
+
+-- name: InsertPageField :exec
+INSERT INTO page_fields (
+    page_id,
+    field,
+    length
+) VALUES (
+    ?,
+    ?,
+    ?
+);
+
+-- name: DeletePageFields :exec
+DELETE FROM page_fields WHERE page_id = ? AND field != 'anchor';
+
+-- name: GetFieldStats :many
+SELECT
+    field,
+    CAST(AVG(length) AS REAL) AS avg_length
+FROM page_fields
+GROUP BY field;
+
+-- name: InsertStubPage :exec
+INSERT INTO pages (
+    url,
+    depth,
+    stub
+) VALUES (
+    ?,
+    ?,
+    TRUE
+) ON CONFLICT (url) DO NOTHING;
+
+-- name: GetPageIDByURL :one
+SELECT id FROM pages WHERE url = ?;
+
+-- name: DeleteLinks :many
+DELETE FROM links WHERE source_page_id = ? RETURNING target_page_id;
+
+-- name: InsertLink :exec
+INSERT INTO links (
+    source_page_id,
+    target_page_id,
+    anchor_text,
+    nofollow
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (source_page_id, target_page_id, anchor_text) DO NOTHING;
+
+-- name: GetAnchorText :many
+SELECT anchor_text FROM links WHERE target_page_id = ? ORDER BY id;
+
+-- name: GetOutlinks :many
+SELECT
+    s.url AS source_url,
+    t.url AS target_url,
+    l.anchor_text
+FROM links AS l
+JOIN pages AS s on s.id = l.source_page_id
+JOIN pages AS t on t.id = l.target_page_id
+WHERE
+    l.source_page_id = sqlc.arg(source_page_id)
+    AND (t.url, l.anchor_text) > (sqlc.arg(after_url), sqlc.arg(after_text))
+ORDER BY t.url, l.anchor_text
+LIMIT sqlc.arg(limit);
+
+-- name: GetBacklinks :many
+SELECT
+    s.url AS source_url,
+    t.url AS target_url,
+    l.anchor_text
+FROM links AS l
+JOIN pages AS s on s.id = l.source_page_id
+JOIN pages AS t on t.id = l.target_page_id
+WHERE
+    l.target_page_id = sqlc.arg(target_page_id)
+    AND (s.url, l.anchor_text) > (sqlc.arg(after_url), sqlc.arg(after_text))
+ORDER BY s.url, l.anchor_text
+LIMIT sqlc.arg(limit);
+
+-- name: ListLinks :many
+SELECT DISTINCT source_page_id, target_page_id
+FROM links
+WHERE source_page_id != target_page_id AND NOT nofollow;
+
+-- name: ListPageRanks :many
+SELECT id, pagerank FROM pages;
+
+-- name: UpdatePageRank :exec
+UPDATE pages SET pagerank = ? WHERE id = ?;
+
+-- name: DeleteFieldTerms :exec
+DELETE FROM page_terms WHERE page_id = ? AND field = ?;
+
+-- name: DeleteField :exec
+DELETE FROM page_fields WHERE page_id = ? AND field = ?;
+
+-- name: DeletePageTerms :exec
+DELETE FROM page_terms WHERE page_id = ? AND field != 'anchor';
 
 -- name: GetPagesForTerm :many
 SELECT
     pt.page_id,
     pt.count,
+    pt.positions,
+    pf.length,
+    p.pagerank,
     origin
 FROM terms AS t
 JOIN page_terms AS pt on t.id = pt.term_id
+JOIN page_fields AS pf on pf.page_id = pt.page_id AND pf.field = pt.field
+JOIN pages AS p on p.id = pt.page_id
 RIGHT JOIN origins AS o on o.page_id = pt.page_id
-WHERE term = ?;
+WHERE term = ? AND pt.field = ?;
+
+-- name: GetCorpusStats :one
+SELECT
+    COUNT(*) FILTER (WHERE NOT stub) AS num_pages,
+    CAST(COALESCE(MAX(id), 0) AS INTEGER) AS max_page_id
+FROM pages;
+
+-- name: ListPages :many
+SELECT id, url, depth, pagerank FROM pages WHERE NOT stub;
+
+-- name: GetPagesForOrigin :many
+SELECT origins.page_id
+FROM origins
+JOIN pages ON pages.id = origins.page_id
+WHERE origins.origin = ?
+    AND NOT pages.stub;
 
 -- name: GetPage :one
 SELECT * FROM pages WHERE id = ?;
 
 -- name: GetOrigins :many
 SELECT origin FROM origins WHERE page_id = ?;
+
+-- name: InsertAlias :exec
+INSERT INTO aliases (
+    url,
+    target_url
+) VALUES (
+    ?,
+    ?
+) ON CONFLICT (url) DO UPDATE SET
+    target_url = excluded.target_url,
+    created_at = CURRENT_TIMESTAMP;
+
+-- name: RetargetAliases :exec
+UPDATE aliases SET target_url = sqlc.arg(new_target_url) WHERE target_url = sqlc.arg(old_target_url);
+
+-- name: DeleteAlias :exec
+DELETE FROM aliases WHERE url = ?;
+
+-- name: GetAliasTarget :one
+SELECT target_url FROM aliases WHERE url = ?;
+
+-- name: MoveLinks :exec
+UPDATE OR IGNORE links SET target_page_id = sqlc.arg(new_page_id) WHERE target_page_id = sqlc.arg(old_page_id);
+
+-- name: MoveOrigins :exec
+UPDATE OR IGNORE origins SET page_id = sqlc.arg(new_page_id) WHERE page_id = sqlc.arg(old_page_id);
+
+-- name: DeletePage :exec
+DELETE FROM pages WHERE id = ?;
+
+-- name: InsertSkipped :exec
+INSERT INTO skipped (
+    url,
+    content_type,
+    reason
+) VALUES (
+    ?,
+    ?,
+    ?
+) ON CONFLICT (url) DO UPDATE SET
+    content_type = excluded.content_type,
+    reason = excluded.reason,
+    created_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteSkipped :exec
+DELETE FROM skipped WHERE url = ?;
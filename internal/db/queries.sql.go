@@ -7,77 +7,1260 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
-const dequeue = `-- name: Dequeue :one
-DELETE FROM queue WHERE id = (
-    SELECT id FROM queue ORDER BY id ASC LIMIT 1
-) RETURNING id, created_at, url, origin, depth, max_depth
+const ackLease = `-- name: AckLease :exec
+DELETE FROM queue WHERE id = ? AND worker_id = ?
 `
 
-func (q *Queries) Dequeue(ctx context.Context) (Queue, error) {
-	row := q.queryRow(ctx, q.dequeueStmt, dequeue)
-	var i Queue
+type AckLeaseParams struct {
+	ID       int64
+	WorkerID sql.NullInt64
+}
+
+func (q *Queries) AckLease(ctx context.Context, arg AckLeaseParams) error {
+	_, err := q.exec(ctx, q.ackLeaseStmt, ackLease, arg.ID, arg.WorkerID)
+	return err
+}
+
+const countCrawlHostPage = `-- name: CountCrawlHostPage :exec
+INSERT INTO crawl_hosts (
+    crawl_id,
+    host,
+    pages
+) VALUES (
+    ?,
+    ?,
+    1
+) ON CONFLICT (crawl_id, host) DO UPDATE SET
+    pages = pages + 1
+`
+
+type CountCrawlHostPageParams struct {
+	CrawlID int64
+	Host    string
+}
+
+func (q *Queries) CountCrawlHostPage(ctx context.Context, arg CountCrawlHostPageParams) error {
+	_, err := q.exec(ctx, q.countCrawlHostPageStmt, countCrawlHostPage, arg.CrawlID, arg.Host)
+	return err
+}
+
+const countCrawlPage = `-- name: CountCrawlPage :exec
+UPDATE crawls SET pages = pages + 1 WHERE id = ?
+`
+
+func (q *Queries) CountCrawlPage(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.countCrawlPageStmt, countCrawlPage, id)
+	return err
+}
+
+const deleteAlias = `-- name: DeleteAlias :exec
+DELETE FROM aliases WHERE url = ?
+`
+
+func (q *Queries) DeleteAlias(ctx context.Context, uRL string) error {
+	_, err := q.exec(ctx, q.deleteAliasStmt, deleteAlias, uRL)
+	return err
+}
+
+const deleteDeadLetter = `-- name: DeleteDeadLetter :exec
+DELETE FROM dead_letters WHERE id = ?
+`
+
+func (q *Queries) DeleteDeadLetter(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteDeadLetterStmt, deleteDeadLetter, id)
+	return err
+}
+
+const deleteField = `-- name: DeleteField :exec
+DELETE FROM page_fields WHERE page_id = ? AND field = ?
+`
+
+type DeleteFieldParams struct {
+	PageID int64
+	Field  string
+}
+
+func (q *Queries) DeleteField(ctx context.Context, arg DeleteFieldParams) error {
+	_, err := q.exec(ctx, q.deleteFieldStmt, deleteField, arg.PageID, arg.Field)
+	return err
+}
+
+const deleteFieldTerms = `-- name: DeleteFieldTerms :exec
+DELETE FROM page_terms WHERE page_id = ? AND field = ?
+`
+
+type DeleteFieldTermsParams struct {
+	PageID int64
+	Field  string
+}
+
+func (q *Queries) DeleteFieldTerms(ctx context.Context, arg DeleteFieldTermsParams) error {
+	_, err := q.exec(ctx, q.deleteFieldTermsStmt, deleteFieldTerms, arg.PageID, arg.Field)
+	return err
+}
+
+const deleteLinks = `-- name: DeleteLinks :many
+DELETE FROM links WHERE source_page_id = ? RETURNING target_page_id
+`
+
+func (q *Queries) DeleteLinks(ctx context.Context, sourcePageID int64) ([]int64, error) {
+	rows, err := q.query(ctx, q.deleteLinksStmt, deleteLinks, sourcePageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var target_page_id int64
+		if err := rows.Scan(&target_page_id); err != nil {
+			return nil, err
+		}
+		items = append(items, target_page_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePage = `-- name: DeletePage :exec
+DELETE FROM pages WHERE id = ?
+`
+
+func (q *Queries) DeletePage(ctx context.Context, iD int64) error {
+	_, err := q.exec(ctx, q.deletePageStmt, deletePage, iD)
+	return err
+}
+
+const deletePageFields = `-- name: DeletePageFields :exec
+DELETE FROM page_fields WHERE page_id = ? AND field != 'anchor'
+`
+
+func (q *Queries) DeletePageFields(ctx context.Context, pageID int64) error {
+	_, err := q.exec(ctx, q.deletePageFieldsStmt, deletePageFields, pageID)
+	return err
+}
+
+const deletePageTerms = `-- name: DeletePageTerms :exec
+DELETE FROM page_terms WHERE page_id = ? AND field != 'anchor'
+`
+
+func (q *Queries) DeletePageTerms(ctx context.Context, pageID int64) error {
+	_, err := q.exec(ctx, q.deletePageTermsStmt, deletePageTerms, pageID)
+	return err
+}
+
+const deleteSkipped = `-- name: DeleteSkipped :exec
+DELETE FROM skipped WHERE url = ?
+`
+
+func (q *Queries) DeleteSkipped(ctx context.Context, uRL string) error {
+	_, err := q.exec(ctx, q.deleteSkippedStmt, deleteSkipped, uRL)
+	return err
+}
+
+const enqueue = `-- name: Enqueue :execrows
+INSERT INTO queue (
+    url,
+    host,
+    origin,
+    depth,
+    max_depth,
+    crawl_id
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (url) DO NOTHING
+`
+
+type EnqueueParams struct {
+	URL      string
+	Host     string
+	Origin   string
+	Depth    int64
+	MaxDepth int64
+	CrawlID  int64
+}
+
+func (q *Queries) Enqueue(ctx context.Context, arg EnqueueParams) (int64, error) {
+	result, err := q.exec(ctx, q.enqueueStmt, enqueue,
+		arg.URL,
+		arg.Host,
+		arg.Origin,
+		arg.Depth,
+		arg.MaxDepth,
+		arg.CrawlID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAliasTarget = `-- name: GetAliasTarget :one
+SELECT target_url FROM aliases WHERE url = ?
+`
+
+func (q *Queries) GetAliasTarget(ctx context.Context, url string) (string, error) {
+	row := q.queryRow(ctx, q.getAliasTargetStmt, getAliasTarget, url)
+	var target_url string
+	err := row.Scan(&target_url)
+	return target_url, err
+}
+
+const getAnchorText = `-- name: GetAnchorText :many
+SELECT anchor_text FROM links WHERE target_page_id = ? ORDER BY id
+`
+
+func (q *Queries) GetAnchorText(ctx context.Context, targetPageID int64) ([]string, error) {
+	rows, err := q.query(ctx, q.getAnchorTextStmt, getAnchorText, targetPageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var anchor_text string
+		if err := rows.Scan(&anchor_text); err != nil {
+			return nil, err
+		}
+		items = append(items, anchor_text)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBacklinks = `-- name: GetBacklinks :many
+SELECT
+    s.url AS source_url,
+    t.url AS target_url,
+    l.anchor_text
+FROM links AS l
+JOIN pages AS s on s.id = l.source_page_id
+JOIN pages AS t on t.id = l.target_page_id
+WHERE
+    l.target_page_id = ?1
+    AND (s.url, l.anchor_text) > (?2, ?3)
+ORDER BY s.url, l.anchor_text
+LIMIT ?4
+`
+
+type GetBacklinksParams struct {
+	TargetPageID int64
+	AfterURL     string
+	AfterText    string
+	Limit        int64
+}
+
+type GetBacklinksRow struct {
+	SourceURL  string
+	TargetURL  string
+	AnchorText string
+}
+
+func (q *Queries) GetBacklinks(ctx context.Context, arg GetBacklinksParams) ([]GetBacklinksRow, error) {
+	rows, err := q.query(ctx, q.getBacklinksStmt, getBacklinks, arg.TargetPageID, arg.AfterURL, arg.AfterText, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBacklinksRow
+	for rows.Next() {
+		var i GetBacklinksRow
+		if err := rows.Scan(
+			&i.SourceURL,
+			&i.TargetURL,
+			&i.AnchorText,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCorpusStats = `-- name: GetCorpusStats :one
+SELECT
+    COUNT(*) FILTER (WHERE NOT stub) AS num_pages,
+    CAST(COALESCE(MAX(id), 0) AS INTEGER) AS max_page_id
+FROM pages
+`
+
+type GetCorpusStatsRow struct {
+	NumPages  int64
+	MaxPageID int64
+}
+
+func (q *Queries) GetCorpusStats(ctx context.Context) (GetCorpusStatsRow, error) {
+	row := q.queryRow(ctx, q.getCorpusStatsStmt, getCorpusStats)
+	var i GetCorpusStatsRow
+	err := row.Scan(&i.NumPages, &i.MaxPageID)
+	return i, err
+}
+
+const getCrawl = `-- name: GetCrawl :one
+SELECT id, created_at, origin, max_depth, scope_mode, include_patterns, exclude_patterns, max_pages, max_pages_per_host, deadline, pages, truncated FROM crawls WHERE id = ?
+`
+
+func (q *Queries) GetCrawl(ctx context.Context, id int64) (Crawl, error) {
+	row := q.queryRow(ctx, q.getCrawlStmt, getCrawl, id)
+	var i Crawl
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Origin,
+		&i.MaxDepth,
+		&i.ScopeMode,
+		&i.IncludePatterns,
+		&i.ExcludePatterns,
+		&i.MaxPages,
+		&i.MaxPagesPerHost,
+		&i.Deadline,
+		&i.Pages,
+		&i.Truncated,
+	)
+	return i, err
+}
+
+const getCrawlHostPages = `-- name: GetCrawlHostPages :one
+SELECT pages FROM crawl_hosts WHERE crawl_id = ? AND host = ?
+`
+
+type GetCrawlHostPagesParams struct {
+	CrawlID int64
+	Host    string
+}
+
+func (q *Queries) GetCrawlHostPages(ctx context.Context, arg GetCrawlHostPagesParams) (int64, error) {
+	row := q.queryRow(ctx, q.getCrawlHostPagesStmt, getCrawlHostPages, arg.CrawlID, arg.Host)
+	var pages int64
+	err := row.Scan(&pages)
+	return pages, err
+}
+
+const getCrawlProgress = `-- name: GetCrawlProgress :one
+SELECT
+    (SELECT COUNT(*) FROM queue WHERE queue.crawl_id = ?1) AS queued,
+    (SELECT COUNT(*) FROM dead_letters WHERE dead_letters.crawl_id = ?1) AS dead_letters
+`
+
+type GetCrawlProgressRow struct {
+	Queued      int64
+	DeadLetters int64
+}
+
+func (q *Queries) GetCrawlProgress(ctx context.Context, crawlID int64) (GetCrawlProgressRow, error) {
+	row := q.queryRow(ctx, q.getCrawlProgressStmt, getCrawlProgress, crawlID)
+	var i GetCrawlProgressRow
+	err := row.Scan(&i.Queued, &i.DeadLetters)
+	return i, err
+}
+
+const getDeadLetter = `-- name: GetDeadLetter :one
+SELECT id, created_at, url, origin, depth, max_depth, attempts, last_error, crawl_id FROM dead_letters WHERE url = ?
+`
+
+func (q *Queries) GetDeadLetter(ctx context.Context, url string) (DeadLetter, error) {
+	row := q.queryRow(ctx, q.getDeadLetterStmt, getDeadLetter, url)
+	var i DeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.URL,
+		&i.Origin,
+		&i.Depth,
+		&i.MaxDepth,
+		&i.Attempts,
+		&i.LastError,
+		&i.CrawlID,
+	)
+	return i, err
+}
+
+const getFieldStats = `-- name: GetFieldStats :many
+SELECT
+    field,
+    CAST(AVG(length) AS REAL) AS avg_length
+FROM page_fields
+GROUP BY field
+`
+
+type GetFieldStatsRow struct {
+	Field     string
+	AvgLength float64
+}
+
+func (q *Queries) GetFieldStats(ctx context.Context) ([]GetFieldStatsRow, error) {
+	rows, err := q.query(ctx, q.getFieldStatsStmt, getFieldStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFieldStatsRow
+	for rows.Next() {
+		var i GetFieldStatsRow
+		if err := rows.Scan(&i.Field, &i.AvgLength); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrigins = `-- name: GetOrigins :many
+SELECT origin FROM origins WHERE page_id = ?
+`
+
+func (q *Queries) GetOrigins(ctx context.Context, pageID int64) ([]string, error) {
+	rows, err := q.query(ctx, q.getOriginsStmt, getOrigins, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var origin string
+		if err := rows.Scan(&origin); err != nil {
+			return nil, err
+		}
+		items = append(items, origin)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOutlinks = `-- name: GetOutlinks :many
+SELECT
+    s.url AS source_url,
+    t.url AS target_url,
+    l.anchor_text
+FROM links AS l
+JOIN pages AS s on s.id = l.source_page_id
+JOIN pages AS t on t.id = l.target_page_id
+WHERE
+    l.source_page_id = ?1
+    AND (t.url, l.anchor_text) > (?2, ?3)
+ORDER BY t.url, l.anchor_text
+LIMIT ?4
+`
+
+type GetOutlinksParams struct {
+	SourcePageID int64
+	AfterURL     string
+	AfterText    string
+	Limit        int64
+}
+
+type GetOutlinksRow struct {
+	SourceURL  string
+	TargetURL  string
+	AnchorText string
+}
+
+func (q *Queries) GetOutlinks(ctx context.Context, arg GetOutlinksParams) ([]GetOutlinksRow, error) {
+	rows, err := q.query(ctx, q.getOutlinksStmt, getOutlinks, arg.SourcePageID, arg.AfterURL, arg.AfterText, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOutlinksRow
+	for rows.Next() {
+		var i GetOutlinksRow
+		if err := rows.Scan(
+			&i.SourceURL,
+			&i.TargetURL,
+			&i.AnchorText,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPage = `-- name: GetPage :one
+SELECT id, created_at, modified_at, url, depth, length, title, text, description, headings, stub, pagerank, etag, last_modified, truncated FROM pages WHERE id = ?
+`
+
+func (q *Queries) GetPage(ctx context.Context, id int64) (Page, error) {
+	row := q.queryRow(ctx, q.getPageStmt, getPage, id)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.URL,
+		&i.Depth,
+		&i.Length,
+		&i.Title,
+		&i.Text,
+		&i.Description,
+		&i.Headings,
+		&i.Stub,
+		&i.Pagerank,
+		&i.Etag,
+		&i.LastModified,
+		&i.Truncated,
+	)
+	return i, err
+}
+
+const getPageIDByURL = `-- name: GetPageIDByURL :one
+SELECT id FROM pages WHERE url = ?
+`
+
+func (q *Queries) GetPageIDByURL(ctx context.Context, url string) (int64, error) {
+	row := q.queryRow(ctx, q.getPageIDByURLStmt, getPageIDByURL, url)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getPagesForOrigin = `-- name: GetPagesForOrigin :many
+SELECT origins.page_id
+FROM origins
+JOIN pages ON pages.id = origins.page_id
+WHERE origins.origin = ?
+    AND NOT pages.stub
+`
+
+func (q *Queries) GetPagesForOrigin(ctx context.Context, origin string) ([]int64, error) {
+	rows, err := q.query(ctx, q.getPagesForOriginStmt, getPagesForOrigin, origin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var page_id int64
+		if err := rows.Scan(&page_id); err != nil {
+			return nil, err
+		}
+		items = append(items, page_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPagesForTerm = `-- name: GetPagesForTerm :many
+SELECT
+    pt.page_id,
+    pt.count,
+    pt.positions,
+    pf.length,
+    p.pagerank,
+    origin
+FROM terms AS t
+JOIN page_terms AS pt on t.id = pt.term_id
+JOIN page_fields AS pf on pf.page_id = pt.page_id AND pf.field = pt.field
+JOIN pages AS p on p.id = pt.page_id
+RIGHT JOIN origins AS o on o.page_id = pt.page_id
+WHERE term = ? AND pt.field = ?
+`
+
+type GetPagesForTermParams struct {
+	Term  string
+	Field string
+}
+
+type GetPagesForTermRow struct {
+	PageID    int64
+	Count     int64
+	Positions []byte
+	Length    int64
+	Pagerank  float64
+	Origin    string
+}
+
+func (q *Queries) GetPagesForTerm(ctx context.Context, arg GetPagesForTermParams) ([]GetPagesForTermRow, error) {
+	rows, err := q.query(ctx, q.getPagesForTermStmt, getPagesForTerm, arg.Term, arg.Field)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPagesForTermRow
+	for rows.Next() {
+		var i GetPagesForTermRow
+		if err := rows.Scan(
+			&i.PageID,
+			&i.Count,
+			&i.Positions,
+			&i.Length,
+			&i.Pagerank,
+			&i.Origin,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQueuedHosts = `-- name: GetQueuedHosts :many
+SELECT host
+FROM queue
+WHERE lease_expires_at IS NULL AND not_before <= ?
+GROUP BY host
+ORDER BY MIN(id) ASC
+LIMIT ?
+`
+
+type GetQueuedHostsParams struct {
+	NotBefore time.Time
+	Limit     int64
+}
+
+func (q *Queries) GetQueuedHosts(ctx context.Context, arg GetQueuedHostsParams) ([]string, error) {
+	rows, err := q.query(ctx, q.getQueuedHostsStmt, getQueuedHosts, arg.NotBefore, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var host string
+		if err := rows.Scan(&host); err != nil {
+			return nil, err
+		}
+		items = append(items, host)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTerm = `-- name: GetTerm :one
+SELECT id, created_at, term FROM terms WHERE term = ?
+`
+
+func (q *Queries) GetTerm(ctx context.Context, term string) (Term, error) {
+	row := q.queryRow(ctx, q.getTermStmt, getTerm, term)
+	var i Term
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.Term)
+	return i, err
+}
+
+const getValidators = `-- name: GetValidators :one
+SELECT etag, last_modified FROM pages WHERE url = ? AND NOT stub
+`
+
+type GetValidatorsRow struct {
+	Etag         string
+	LastModified string
+}
+
+func (q *Queries) GetValidators(ctx context.Context, url string) (GetValidatorsRow, error) {
+	row := q.queryRow(ctx, q.getValidatorsStmt, getValidators, url)
+	var i GetValidatorsRow
+	err := row.Scan(&i.Etag, &i.LastModified)
+	return i, err
+}
+
+const insertAlias = `-- name: InsertAlias :exec
+INSERT INTO aliases (
+    url,
+    target_url
+) VALUES (
+    ?,
+    ?
+) ON CONFLICT (url) DO UPDATE SET
+    target_url = excluded.target_url,
+    created_at = CURRENT_TIMESTAMP
+`
+
+type InsertAliasParams struct {
+	URL       string
+	TargetURL string
+}
+
+func (q *Queries) InsertAlias(ctx context.Context, arg InsertAliasParams) error {
+	_, err := q.exec(ctx, q.insertAliasStmt, insertAlias, arg.URL, arg.TargetURL)
+	return err
+}
+
+const insertCrawl = `-- name: InsertCrawl :one
+INSERT INTO crawls (
+    origin,
+    max_depth,
+    scope_mode,
+    include_patterns,
+    exclude_patterns,
+    max_pages,
+    max_pages_per_host,
+    deadline
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) RETURNING id, created_at, origin, max_depth, scope_mode, include_patterns, exclude_patterns, max_pages, max_pages_per_host, deadline, pages, truncated
+`
+
+type InsertCrawlParams struct {
+	Origin          string
+	MaxDepth        int64
+	ScopeMode       int64
+	IncludePatterns string
+	ExcludePatterns string
+	MaxPages        int64
+	MaxPagesPerHost int64
+	Deadline        sql.NullTime
+}
+
+func (q *Queries) InsertCrawl(ctx context.Context, arg InsertCrawlParams) (Crawl, error) {
+	row := q.queryRow(ctx, q.insertCrawlStmt, insertCrawl,
+		arg.Origin,
+		arg.MaxDepth,
+		arg.ScopeMode,
+		arg.IncludePatterns,
+		arg.ExcludePatterns,
+		arg.MaxPages,
+		arg.MaxPagesPerHost,
+		arg.Deadline,
+	)
+	var i Crawl
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Origin,
+		&i.MaxDepth,
+		&i.ScopeMode,
+		&i.IncludePatterns,
+		&i.ExcludePatterns,
+		&i.MaxPages,
+		&i.MaxPagesPerHost,
+		&i.Deadline,
+		&i.Pages,
+		&i.Truncated,
+	)
+	return i, err
+}
+
+const insertDeadLetter = `-- name: InsertDeadLetter :exec
+INSERT INTO dead_letters (
+    url,
+    origin,
+    depth,
+    max_depth,
+    attempts,
+    last_error,
+    crawl_id
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (url) DO UPDATE
+SET
+    created_at = CURRENT_TIMESTAMP,
+    attempts = excluded.attempts,
+    last_error = excluded.last_error
+`
+
+type InsertDeadLetterParams struct {
+	URL       string
+	Origin    string
+	Depth     int64
+	MaxDepth  int64
+	Attempts  int64
+	LastError string
+	CrawlID   int64
+}
+
+func (q *Queries) InsertDeadLetter(ctx context.Context, arg InsertDeadLetterParams) error {
+	_, err := q.exec(ctx, q.insertDeadLetterStmt, insertDeadLetter,
+		arg.URL,
+		arg.Origin,
+		arg.Depth,
+		arg.MaxDepth,
+		arg.Attempts,
+		arg.LastError,
+		arg.CrawlID,
+	)
+	return err
+}
+
+const insertLink = `-- name: InsertLink :exec
+INSERT INTO links (
+    source_page_id,
+    target_page_id,
+    anchor_text,
+    nofollow
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (source_page_id, target_page_id, anchor_text) DO NOTHING
+`
+
+type InsertLinkParams struct {
+	SourcePageID int64
+	TargetPageID int64
+	AnchorText   string
+	Nofollow     bool
+}
+
+func (q *Queries) InsertLink(ctx context.Context, arg InsertLinkParams) error {
+	_, err := q.exec(ctx, q.insertLinkStmt, insertLink,
+		arg.SourcePageID,
+		arg.TargetPageID,
+		arg.AnchorText,
+		arg.Nofollow,
+	)
+	return err
+}
+
+const insertOrigin = `-- name: InsertOrigin :exec
+INSERT INTO origins (
+    page_id,
+    origin
+) VALUES (
+    ?,
+    ?
+) ON CONFLICT (page_id, origin) DO NOTHING
+`
+
+type InsertOriginParams struct {
+	PageID int64
+	Origin string
+}
+
+func (q *Queries) InsertOrigin(ctx context.Context, arg InsertOriginParams) error {
+	_, err := q.exec(ctx, q.insertOriginStmt, insertOrigin, arg.PageID, arg.Origin)
+	return err
+}
+
+const insertPage = `-- name: InsertPage :one
+INSERT INTO pages (
+    url,
+    depth,
+    length,
+    title,
+    text,
+    description,
+    headings,
+    etag,
+    last_modified,
+    truncated
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (url) DO NOTHING
+RETURNING id, created_at, modified_at, url, depth, length, title, text, description, headings, stub, pagerank, etag, last_modified, truncated
+`
+
+type InsertPageParams struct {
+	URL          string
+	Depth        int64
+	Length       int64
+	Title        string
+	Text         []byte
+	Description  string
+	Headings     string
+	Etag         string
+	LastModified string
+	Truncated    bool
+}
+
+func (q *Queries) InsertPage(ctx context.Context, arg InsertPageParams) (Page, error) {
+	row := q.queryRow(ctx, q.insertPageStmt, insertPage,
+		arg.URL,
+		arg.Depth,
+		arg.Length,
+		arg.Title,
+		arg.Text,
+		arg.Description,
+		arg.Headings,
+		arg.Etag,
+		arg.LastModified,
+		arg.Truncated,
+	)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.URL,
+		&i.Depth,
+		&i.Length,
+		&i.Title,
+		&i.Text,
+		&i.Description,
+		&i.Headings,
+		&i.Stub,
+		&i.Pagerank,
+		&i.Etag,
+		&i.LastModified,
+		&i.Truncated,
+	)
+	return i, err
+}
+
+const insertPageField = `-- name: InsertPageField :exec
+INSERT INTO page_fields (
+    page_id,
+    field,
+    length
+) VALUES (
+    ?,
+    ?,
+    ?
+)
+`
+
+type InsertPageFieldParams struct {
+	PageID int64
+	Field  string
+	Length int64
+}
+
+func (q *Queries) InsertPageField(ctx context.Context, arg InsertPageFieldParams) error {
+	_, err := q.exec(ctx, q.insertPageFieldStmt, insertPageField, arg.PageID, arg.Field, arg.Length)
+	return err
+}
+
+const insertPageTerm = `-- name: InsertPageTerm :exec
+INSERT INTO page_terms (
+    page_id,
+    term_id,
+    count,
+    positions,
+    field
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (page_id, term_id, field) DO UPDATE
+SET count = excluded.count, positions = excluded.positions
+`
+
+type InsertPageTermParams struct {
+	PageID    int64
+	TermID    int64
+	Count     int64
+	Positions []byte
+	Field     string
+}
+
+func (q *Queries) InsertPageTerm(ctx context.Context, arg InsertPageTermParams) error {
+	_, err := q.exec(ctx, q.insertPageTermStmt, insertPageTerm,
+		arg.PageID,
+		arg.TermID,
+		arg.Count,
+		arg.Positions,
+		arg.Field,
+	)
+	return err
+}
+
+const insertSkipped = `-- name: InsertSkipped :exec
+INSERT INTO skipped (
+    url,
+    content_type,
+    reason
+) VALUES (
+    ?,
+    ?,
+    ?
+) ON CONFLICT (url) DO UPDATE SET
+    content_type = excluded.content_type,
+    reason = excluded.reason,
+    created_at = CURRENT_TIMESTAMP
+`
+
+type InsertSkippedParams struct {
+	URL         string
+	ContentType string
+	Reason      string
+}
+
+func (q *Queries) InsertSkipped(ctx context.Context, arg InsertSkippedParams) error {
+	_, err := q.exec(ctx, q.insertSkippedStmt, insertSkipped, arg.URL, arg.ContentType, arg.Reason)
+	return err
+}
+
+const insertStubPage = `-- name: InsertStubPage :exec
+INSERT INTO pages (
+    url,
+    depth,
+    stub
+) VALUES (
+    ?,
+    ?,
+    TRUE
+) ON CONFLICT (url) DO NOTHING
+`
+
+type InsertStubPageParams struct {
+	URL   string
+	Depth int64
+}
+
+func (q *Queries) InsertStubPage(ctx context.Context, arg InsertStubPageParams) error {
+	_, err := q.exec(ctx, q.insertStubPageStmt, insertStubPage, arg.URL, arg.Depth)
+	return err
+}
+
+const insertTerm = `-- name: InsertTerm :one
+INSERT INTO terms (
+    term
+) VALUES (
+    ?
+) ON CONFLICT (term) DO NOTHING
+RETURNING id, created_at, term
+`
+
+func (q *Queries) InsertTerm(ctx context.Context, term string) (Term, error) {
+	row := q.queryRow(ctx, q.insertTermStmt, insertTerm, term)
+	var i Term
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.Term)
+	return i, err
+}
+
+const isIndexed = `-- name: IsIndexed :one
+SELECT id, created_at, modified_at, url, depth, length, title, text, description, headings, stub, pagerank, etag, last_modified, truncated
+FROM pages
+WHERE
+    url = COALESCE((SELECT target_url FROM aliases WHERE aliases.url = ?1), ?1)
+    AND modified_at >= ?2
+    AND NOT stub
+`
+
+type IsIndexedParams struct {
+	URL        string
+	ModifiedAt time.Time
+}
+
+func (q *Queries) IsIndexed(ctx context.Context, arg IsIndexedParams) (Page, error) {
+	row := q.queryRow(ctx, q.isIndexedStmt, isIndexed, arg.URL, arg.ModifiedAt)
+	var i Page
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
+		&i.ModifiedAt,
 		&i.URL,
-		&i.Origin,
 		&i.Depth,
-		&i.MaxDepth,
+		&i.Length,
+		&i.Title,
+		&i.Text,
+		&i.Description,
+		&i.Headings,
+		&i.Stub,
+		&i.Pagerank,
+		&i.Etag,
+		&i.LastModified,
+		&i.Truncated,
 	)
 	return i, err
 }
 
-const enqueue = `-- name: Enqueue :exec
-INSERT INTO queue (
-    url,
-    origin,
-    depth,
-    max_depth
-) VALUES (
-    ?,
-    ?,
-    ?,
-    ?
-) ON CONFLICT (url) DO NOTHING
+const isQueued = `-- name: IsQueued :one
+SELECT EXISTS(SELECT 1 FROM queue WHERE url = ?)
 `
 
-type EnqueueParams struct {
-	URL      string
-	Origin   string
-	Depth    int64
-	MaxDepth int64
+func (q *Queries) IsQueued(ctx context.Context, url string) (int64, error) {
+	row := q.queryRow(ctx, q.isQueuedStmt, isQueued, url)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
 }
 
-func (q *Queries) Enqueue(ctx context.Context, arg EnqueueParams) error {
-	_, err := q.exec(ctx, q.enqueueStmt, enqueue,
-		arg.URL,
-		arg.Origin,
-		arg.Depth,
-		arg.MaxDepth,
+const isSkipped = `-- name: IsSkipped :one
+SELECT EXISTS(
+    SELECT 1
+    FROM skipped
+    WHERE
+        url = COALESCE((SELECT target_url FROM aliases WHERE aliases.url = ?1), ?1)
+        AND created_at >= ?2
+)
+`
+
+type IsSkippedParams struct {
+	URL       string
+	CreatedAt time.Time
+}
+
+func (q *Queries) IsSkipped(ctx context.Context, arg IsSkippedParams) (int64, error) {
+	row := q.queryRow(ctx, q.isSkippedStmt, isSkipped, arg.URL, arg.CreatedAt)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const leaseHost = `-- name: LeaseHost :one
+UPDATE queue SET lease_expires_at = ?, worker_id = ? WHERE id = (
+    SELECT id
+    FROM queue
+    WHERE host = ? AND lease_expires_at IS NULL AND not_before <= ?
+    ORDER BY id ASC
+    LIMIT 1
+) RETURNING id, created_at, url, host, origin, depth, max_depth, lease_expires_at, worker_id, attempts, not_before, last_error, crawl_id
+`
+
+type LeaseHostParams struct {
+	LeaseExpiresAt sql.NullTime
+	WorkerID       sql.NullInt64
+	Host           string
+	NotBefore      time.Time
+}
+
+func (q *Queries) LeaseHost(ctx context.Context, arg LeaseHostParams) (Queue, error) {
+	row := q.queryRow(ctx, q.leaseHostStmt, leaseHost,
+		arg.LeaseExpiresAt,
+		arg.WorkerID,
+		arg.Host,
+		arg.NotBefore,
 	)
-	return err
+	var i Queue
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.URL,
+		&i.Host,
+		&i.Origin,
+		&i.Depth,
+		&i.MaxDepth,
+		&i.LeaseExpiresAt,
+		&i.WorkerID,
+		&i.Attempts,
+		&i.NotBefore,
+		&i.LastError,
+		&i.CrawlID,
+	)
+	return i, err
 }
 
-const getOrigins = `-- name: GetOrigins :many
-SELECT origin FROM origins WHERE page_id = ?
+const listDeadLetters = `-- name: ListDeadLetters :many
+SELECT id, created_at, url, origin, depth, max_depth, attempts, last_error, crawl_id FROM dead_letters ORDER BY id ASC
 `
 
-func (q *Queries) GetOrigins(ctx context.Context, pageID int64) ([]string, error) {
-	rows, err := q.query(ctx, q.getOriginsStmt, getOrigins, pageID)
+func (q *Queries) ListDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	rows, err := q.query(ctx, q.listDeadLettersStmt, listDeadLetters)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeadLetter
+	for rows.Next() {
+		var i DeadLetter
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.URL,
+			&i.Origin,
+			&i.Depth,
+			&i.MaxDepth,
+			&i.Attempts,
+			&i.LastError,
+			&i.CrawlID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowLinks = `-- name: ListFollowLinks :many
+SELECT DISTINCT t.url
+FROM links AS l
+JOIN pages AS s on s.id = l.source_page_id
+JOIN pages AS t on t.id = l.target_page_id
+WHERE s.url = ? AND NOT l.nofollow
+ORDER BY l.id
+`
+
+func (q *Queries) ListFollowLinks(ctx context.Context, url string) ([]string, error) {
+	rows, err := q.query(ctx, q.listFollowLinksStmt, listFollowLinks, url)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var items []string
 	for rows.Next() {
-		var origin string
-		if err := rows.Scan(&origin); err != nil {
+		var url string
+		if err := rows.Scan(&url); err != nil {
 			return nil, err
 		}
-		items = append(items, origin)
+		items = append(items, url)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -88,50 +1271,65 @@ func (q *Queries) GetOrigins(ctx context.Context, pageID int64) ([]string, error
 	return items, nil
 }
 
-const getPage = `-- name: GetPage :one
-SELECT id, created_at, modified_at, url, depth FROM pages WHERE id = ?
+const listLinks = `-- name: ListLinks :many
+SELECT DISTINCT source_page_id, target_page_id
+FROM links
+WHERE source_page_id != target_page_id AND NOT nofollow
 `
 
-func (q *Queries) GetPage(ctx context.Context, id int64) (Page, error) {
-	row := q.queryRow(ctx, q.getPageStmt, getPage, id)
-	var i Page
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.ModifiedAt,
-		&i.URL,
-		&i.Depth,
-	)
-	return i, err
+type ListLinksRow struct {
+	SourcePageID int64
+	TargetPageID int64
 }
 
-const getPagesForTerm = `-- name: GetPagesForTerm :many
-SELECT
-    pt.page_id,
-    pt.count,
-    origin
-FROM terms AS t
-JOIN page_terms AS pt on t.id = pt.term_id
-RIGHT JOIN origins AS o on o.page_id = pt.page_id
-WHERE term = ?
+func (q *Queries) ListLinks(ctx context.Context) ([]ListLinksRow, error) {
+	rows, err := q.query(ctx, q.listLinksStmt, listLinks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLinksRow
+	for rows.Next() {
+		var i ListLinksRow
+		if err := rows.Scan(
+			&i.SourcePageID,
+			&i.TargetPageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPageRanks = `-- name: ListPageRanks :many
+SELECT id, pagerank FROM pages
 `
 
-type GetPagesForTermRow struct {
-	PageID int64
-	Count  int64
-	Origin string
+type ListPageRanksRow struct {
+	ID       int64
+	Pagerank float64
 }
 
-func (q *Queries) GetPagesForTerm(ctx context.Context, term string) ([]GetPagesForTermRow, error) {
-	rows, err := q.query(ctx, q.getPagesForTermStmt, getPagesForTerm, term)
+func (q *Queries) ListPageRanks(ctx context.Context) ([]ListPageRanksRow, error) {
+	rows, err := q.query(ctx, q.listPageRanksStmt, listPageRanks)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPagesForTermRow
+	var items []ListPageRanksRow
 	for rows.Next() {
-		var i GetPagesForTermRow
-		if err := rows.Scan(&i.PageID, &i.Count, &i.Origin); err != nil {
+		var i ListPageRanksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Pagerank,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -145,140 +1343,189 @@ func (q *Queries) GetPagesForTerm(ctx context.Context, term string) ([]GetPagesF
 	return items, nil
 }
 
-const getTerm = `-- name: GetTerm :one
-SELECT id, created_at, term FROM terms WHERE term = ?
+const listPages = `-- name: ListPages :many
+SELECT id, url, depth, pagerank FROM pages WHERE NOT stub
 `
 
-func (q *Queries) GetTerm(ctx context.Context, term string) (Term, error) {
-	row := q.queryRow(ctx, q.getTermStmt, getTerm, term)
-	var i Term
-	err := row.Scan(&i.ID, &i.CreatedAt, &i.Term)
-	return i, err
+type ListPagesRow struct {
+	ID       int64
+	URL      string
+	Depth    int64
+	Pagerank float64
 }
 
-const insertOrigin = `-- name: InsertOrigin :exec
-INSERT INTO origins (
-    page_id,
-    origin
-) VALUES (
-    ?,
-    ?
-) ON CONFLICT (page_id, origin) DO NOTHING
+func (q *Queries) ListPages(ctx context.Context) ([]ListPagesRow, error) {
+	rows, err := q.query(ctx, q.listPagesStmt, listPages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPagesRow
+	for rows.Next() {
+		var i ListPagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.URL,
+			&i.Depth,
+			&i.Pagerank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveLinks = `-- name: MoveLinks :exec
+UPDATE OR IGNORE links SET target_page_id = ?1 WHERE target_page_id = ?2
 `
 
-type InsertOriginParams struct {
-	PageID int64
-	Origin string
+type MoveLinksParams struct {
+	NewPageID int64
+	OldPageID int64
 }
 
-func (q *Queries) InsertOrigin(ctx context.Context, arg InsertOriginParams) error {
-	_, err := q.exec(ctx, q.insertOriginStmt, insertOrigin, arg.PageID, arg.Origin)
+func (q *Queries) MoveLinks(ctx context.Context, arg MoveLinksParams) error {
+	_, err := q.exec(ctx, q.moveLinksStmt, moveLinks, arg.NewPageID, arg.OldPageID)
 	return err
 }
 
-const insertPage = `-- name: InsertPage :one
-INSERT INTO pages (
-    url,
-    depth
-) VALUES (
-    ?,
-    ?
-) ON CONFLICT (url) DO NOTHING
-RETURNING id, created_at, modified_at, url, depth
+const moveOrigins = `-- name: MoveOrigins :exec
+UPDATE OR IGNORE origins SET page_id = ?1 WHERE page_id = ?2
 `
 
-type InsertPageParams struct {
-	URL   string
-	Depth int64
+type MoveOriginsParams struct {
+	NewPageID int64
+	OldPageID int64
 }
 
-func (q *Queries) InsertPage(ctx context.Context, arg InsertPageParams) (Page, error) {
-	row := q.queryRow(ctx, q.insertPageStmt, insertPage, arg.URL, arg.Depth)
-	var i Page
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.ModifiedAt,
-		&i.URL,
-		&i.Depth,
-	)
-	return i, err
+func (q *Queries) MoveOrigins(ctx context.Context, arg MoveOriginsParams) error {
+	_, err := q.exec(ctx, q.moveOriginsStmt, moveOrigins, arg.NewPageID, arg.OldPageID)
+	return err
 }
 
-const insertPageTerm = `-- name: InsertPageTerm :exec
-INSERT INTO page_terms (
-    page_id,
-    term_id
-) VALUES (
-    ?,
-    ?
-) ON CONFLICT (page_id, term_id) DO UPDATE
-SET count = page_terms.count + 1
+const releaseLease = `-- name: ReleaseLease :exec
+UPDATE queue SET lease_expires_at = NULL, worker_id = NULL WHERE id = ? AND worker_id = ?
 `
 
-type InsertPageTermParams struct {
-	PageID int64
-	TermID int64
+type ReleaseLeaseParams struct {
+	ID       int64
+	WorkerID sql.NullInt64
 }
 
-func (q *Queries) InsertPageTerm(ctx context.Context, arg InsertPageTermParams) error {
-	_, err := q.exec(ctx, q.insertPageTermStmt, insertPageTerm, arg.PageID, arg.TermID)
+func (q *Queries) ReleaseLease(ctx context.Context, arg ReleaseLeaseParams) error {
+	_, err := q.exec(ctx, q.releaseLeaseStmt, releaseLease, arg.ID, arg.WorkerID)
 	return err
 }
 
-const insertTerm = `-- name: InsertTerm :one
-INSERT INTO terms (
-    term
-) VALUES (
-    ?
-) ON CONFLICT (term) DO NOTHING
-RETURNING id, created_at, term
+const requeueExpiredLeases = `-- name: RequeueExpiredLeases :execrows
+UPDATE queue SET lease_expires_at = NULL, worker_id = NULL WHERE lease_expires_at < ?
 `
 
-func (q *Queries) InsertTerm(ctx context.Context, term string) (Term, error) {
-	row := q.queryRow(ctx, q.insertTermStmt, insertTerm, term)
-	var i Term
-	err := row.Scan(&i.ID, &i.CreatedAt, &i.Term)
-	return i, err
+func (q *Queries) RequeueExpiredLeases(ctx context.Context, leaseExpiresAt sql.NullTime) (int64, error) {
+	result, err := q.exec(ctx, q.requeueExpiredLeasesStmt, requeueExpiredLeases, leaseExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const isIndexed = `-- name: IsIndexed :one
-SELECT id, created_at, modified_at, url, depth
-FROM pages
-WHERE
-    url = ?
-    AND modified_at >= ?
+const retargetAliases = `-- name: RetargetAliases :exec
+UPDATE aliases SET target_url = ?1 WHERE target_url = ?2
 `
 
-type IsIndexedParams struct {
-	URL        string
-	ModifiedAt time.Time
+type RetargetAliasesParams struct {
+	NewTargetURL string
+	OldTargetURL string
 }
 
-func (q *Queries) IsIndexed(ctx context.Context, arg IsIndexedParams) (Page, error) {
-	row := q.queryRow(ctx, q.isIndexedStmt, isIndexed, arg.URL, arg.ModifiedAt)
-	var i Page
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.ModifiedAt,
-		&i.URL,
-		&i.Depth,
+func (q *Queries) RetargetAliases(ctx context.Context, arg RetargetAliasesParams) error {
+	_, err := q.exec(ctx, q.retargetAliasesStmt, retargetAliases, arg.NewTargetURL, arg.OldTargetURL)
+	return err
+}
+
+const retryLease = `-- name: RetryLease :exec
+UPDATE queue
+SET
+    lease_expires_at = NULL,
+    worker_id = NULL,
+    attempts = attempts + 1,
+    not_before = ?,
+    last_error = ?
+WHERE id = ? AND worker_id = ?
+`
+
+type RetryLeaseParams struct {
+	NotBefore time.Time
+	LastError string
+	ID        int64
+	WorkerID  sql.NullInt64
+}
+
+func (q *Queries) RetryLease(ctx context.Context, arg RetryLeaseParams) error {
+	_, err := q.exec(ctx, q.retryLeaseStmt, retryLease,
+		arg.NotBefore,
+		arg.LastError,
+		arg.ID,
+		arg.WorkerID,
 	)
-	return i, err
+	return err
+}
+
+const touchPage = `-- name: TouchPage :exec
+UPDATE pages SET modified_at = CURRENT_TIMESTAMP WHERE url = ?
+`
+
+func (q *Queries) TouchPage(ctx context.Context, url string) error {
+	_, err := q.exec(ctx, q.touchPageStmt, touchPage, url)
+	return err
+}
+
+const truncateCrawl = `-- name: TruncateCrawl :exec
+UPDATE crawls SET truncated = TRUE WHERE id = ?
+`
+
+func (q *Queries) TruncateCrawl(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.truncateCrawlStmt, truncateCrawl, id)
+	return err
 }
 
 const updatePage = `-- name: UpdatePage :one
-UPDATE pages SET depth = ?, modified_at = CURRENT_TIMESTAMP WHERE url = ? RETURNING id, created_at, modified_at, url, depth
+UPDATE pages SET depth = ?, length = ?, title = ?, text = ?, description = ?, headings = ?, etag = ?, last_modified = ?, truncated = ?, stub = FALSE, modified_at = CURRENT_TIMESTAMP WHERE url = ? RETURNING id, created_at, modified_at, url, depth, length, title, text, description, headings, stub, pagerank, etag, last_modified, truncated
 `
 
 type UpdatePageParams struct {
-	Depth int64
-	URL   string
+	Depth        int64
+	Length       int64
+	Title        string
+	Text         []byte
+	Description  string
+	Headings     string
+	Etag         string
+	LastModified string
+	Truncated    bool
+	URL          string
 }
 
 func (q *Queries) UpdatePage(ctx context.Context, arg UpdatePageParams) (Page, error) {
-	row := q.queryRow(ctx, q.updatePageStmt, updatePage, arg.Depth, arg.URL)
+	row := q.queryRow(ctx, q.updatePageStmt, updatePage,
+		arg.Depth,
+		arg.Length,
+		arg.Title,
+		arg.Text,
+		arg.Description,
+		arg.Headings,
+		arg.Etag,
+		arg.LastModified,
+		arg.Truncated,
+		arg.URL,
+	)
 	var i Page
 	err := row.Scan(
 		&i.ID,
@@ -286,6 +1533,30 @@ func (q *Queries) UpdatePage(ctx context.Context, arg UpdatePageParams) (Page, e
 		&i.ModifiedAt,
 		&i.URL,
 		&i.Depth,
+		&i.Length,
+		&i.Title,
+		&i.Text,
+		&i.Description,
+		&i.Headings,
+		&i.Stub,
+		&i.Pagerank,
+		&i.Etag,
+		&i.LastModified,
+		&i.Truncated,
 	)
 	return i, err
 }
+
+const updatePageRank = `-- name: UpdatePageRank :exec
+UPDATE pages SET pagerank = ? WHERE id = ?
+`
+
+type UpdatePageRankParams struct {
+	Pagerank float64
+	ID       int64
+}
+
+func (q *Queries) UpdatePageRank(ctx context.Context, arg UpdatePageRankParams) error {
+	_, err := q.exec(ctx, q.updatePageRankStmt, updatePageRank, arg.Pagerank, arg.ID)
+	return err
+}
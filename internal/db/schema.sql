@@ -2,9 +2,63 @@ CREATE TABLE IF NOT EXISTS queue (
     id INTEGER PRIMARY KEY,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     url TEXT NOT NULL UNIQUE,
+    host TEXT NOT NULL,
     origin TEXT NOT NULL,
     depth INTEGER NOT NULL,
-    max_depth INTEGER NOT NULL
+    max_depth INTEGER NOT NULL,
+    lease_expires_at TIMESTAMP,
+    worker_id INTEGER,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    not_before TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    crawl_id INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS queue_host_idx ON queue (host, id);
+CREATE INDEX IF NOT EXISTS queue_lease_idx ON queue (lease_expires_at);
+CREATE INDEX IF NOT EXISTS queue_crawl_idx ON queue (crawl_id);
+
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    origin TEXT NOT NULL,
+    depth INTEGER NOT NULL,
+    max_depth INTEGER NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    crawl_id INTEGER NOT NULL DEFAULT 0
+);
+
+-- crawls are the index requests that urls are queued for, the links of a page
+-- are only queued if they are within the scope of its crawl
+CREATE TABLE IF NOT EXISTS crawls (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    origin TEXT NOT NULL,
+    max_depth INTEGER NOT NULL,
+    scope_mode INTEGER NOT NULL DEFAULT 0,
+    -- json arrays of url patterns
+    include_patterns TEXT NOT NULL DEFAULT '[]',
+    exclude_patterns TEXT NOT NULL DEFAULT '[]',
+    -- budgets, zero is unlimited. once one is exhausted no more urls are
+    -- queued for the crawl and it is marked as truncated.
+    max_pages INTEGER NOT NULL DEFAULT 0,
+    max_pages_per_host INTEGER NOT NULL DEFAULT 0,
+    deadline TIMESTAMP,
+    -- the number of urls that have been queued for the crawl
+    pages INTEGER NOT NULL DEFAULT 0,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS crawl_hosts (
+    id INTEGER PRIMARY KEY,
+    crawl_id INTEGER NOT NULL,
+    host TEXT NOT NULL,
+    -- the number of urls on the host that have been queued for the crawl
+    pages INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (crawl_id) REFERENCES crawls (id) ON DELETE CASCADE,
+    UNIQUE (crawl_id, host)
 );
 
 CREATE TABLE IF NOT EXISTS pages (
@@ -12,9 +66,72 @@ CREATE TABLE IF NOT EXISTS pages (
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     modified_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     url TEXT NOT NULL UNIQUE,
-    depth INTEGER NOT NULL
+    depth INTEGER NOT NULL,
+    length INTEGER NOT NULL DEFAULT 0,
+    title TEXT NOT NULL DEFAULT '',
+    text BLOB NOT NULL DEFAULT x'',
+    description TEXT NOT NULL DEFAULT '',
+    headings TEXT NOT NULL DEFAULT '',
+    -- stub pages have been linked to but not yet indexed
+    stub BOOLEAN NOT NULL DEFAULT FALSE,
+    -- importance computed from the link graph, from 0 to 1
+    pagerank REAL NOT NULL DEFAULT 0,
+    -- validators from the last fetch, sent to only refetch the page if it
+    -- has changed
+    etag TEXT NOT NULL DEFAULT '',
+    last_modified TEXT NOT NULL DEFAULT '',
+    -- the body was longer than the maximum that is read, only the start of
+    -- it was indexed
+    truncated BOOLEAN NOT NULL DEFAULT FALSE
 );
 
+-- aliases are urls that redirect to another url, they are resolved to the
+-- final url of the redirect chain, the one the page is indexed under
+CREATE TABLE IF NOT EXISTS aliases (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    target_url TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS aliases_target_idx ON aliases (target_url);
+
+-- skipped are urls that were fetched but not indexed because no extractor
+-- supports their content type, or extraction failed
+CREATE TABLE IF NOT EXISTS skipped (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    url TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    reason TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS page_fields (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    page_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    length INTEGER NOT NULL,
+    FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    UNIQUE (page_id, field)
+);
+
+-- links are the edges of the link graph, from a page to every url it links to
+CREATE TABLE IF NOT EXISTS links (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    source_page_id INTEGER NOT NULL,
+    target_page_id INTEGER NOT NULL,
+    anchor_text TEXT NOT NULL,
+    -- nofollow links aren't crawled and don't count towards pagerank
+    nofollow BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (source_page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    FOREIGN KEY (target_page_id) REFERENCES pages (id) ON DELETE CASCADE,
+    UNIQUE (source_page_id, target_page_id, anchor_text)
+);
+
+CREATE INDEX IF NOT EXISTS links_target_idx ON links (target_page_id);
+
 CREATE TABLE IF NOT EXISTS origins (
     id INTEGER PRIMARY KEY,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -36,7 +153,9 @@ CREATE TABLE IF NOT EXISTS page_terms (
     page_id INTEGER NOT NULL,
     term_id INTEGER NOT NULL,
     count INTEGER NOT NULL DEFAULT 1,
+    positions BLOB NOT NULL DEFAULT x'',
+    field TEXT NOT NULL DEFAULT 'body',
     FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
     FOREIGN KEY (page_id) REFERENCES pages (id) ON DELETE CASCADE,
-    UNIQUE (page_id, term_id)
+    UNIQUE (page_id, term_id, field)
 );
@@ -0,0 +1,89 @@
+package extract
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// hasRel reports whether a link relation, a space separated list of link
+// types, includes the given type
+func hasRel(rel, typ string) bool {
+	return slices.ContainsFunc(strings.Fields(rel), func(t string) bool {
+		return strings.EqualFold(t, typ)
+	})
+}
+
+// isCanonical reports whether a link relation includes canonical
+func isCanonical(rel string) bool {
+	return hasRel(rel, "canonical")
+}
+
+// CanonicalHeader returns the canonical url from the Link headers, e.g.
+// `Link: <https://example.com/page>; rel="canonical"`, resolved against the
+// url of the response
+func CanonicalHeader(h http.Header, base *url.URL) *url.URL {
+	for _, v := range h.Values("Link") {
+		for _, link := range splitLinks(v) {
+			target, params, ok := strings.Cut(link, ";")
+			target = strings.TrimSpace(target)
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, val, _ := strings.Cut(param, "=")
+				if !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				if !isCanonical(strings.Trim(strings.TrimSpace(val), `"`)) {
+					continue
+				}
+				if u, ok := canonicalURL(base, target[1:len(target)-1]); ok {
+					return u
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitLinks splits the value of a Link header into its links, ignoring commas
+// within the url or quoted parameters
+func splitLinks(v string) []string {
+	var (
+		ret    []string
+		start  int
+		inURL  bool
+		quoted bool
+	)
+
+	for i, r := range v {
+		switch {
+		case r == '<' && !quoted:
+			inURL = true
+		case r == '>' && !quoted:
+			inURL = false
+		case r == '"' && !inURL:
+			quoted = !quoted
+		case r == ',' && !inURL && !quoted:
+			ret = append(ret, v[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(ret, v[start:])
+}
+
+// canonicalURL resolves the href of a canonical link. only http and https
+// urls are valid.
+func canonicalURL(base *url.URL, href string) (*url.URL, bool) {
+	u, err := base.Parse(strings.TrimSpace(href))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, false
+	}
+
+	return u, true
+}
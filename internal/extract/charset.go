@@ -0,0 +1,123 @@
+package extract
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// prescanLen is the most bytes charset.DetermineEncoding considers
+const prescanLen = 1024
+
+// isText reports whether the media type is text that may be in any character
+// encoding, rather than a binary format like pdf
+func isText(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "+xml") ||
+		mediaType == "application/xml"
+}
+
+// Decode returns a reader of the body transcoded to utf-8, along with the name
+// of the encoding it was in. the encoding is determined by a byte order mark,
+// the charset parameter of the content type or a <meta charset> tag and is
+// otherwise sniffed from the start of the body. bodies that aren't text are
+// returned as is.
+func Decode(body *bufio.Reader, contentType, mediaType string) (io.Reader, string) {
+	if !isText(mediaType) {
+		return body, ""
+	}
+
+	data, err := body.Peek(prescanLen)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return body, ""
+	}
+
+	e, name, certain := charset.DetermineEncoding(data, contentType)
+	if !certain && name == "windows-1252" && isUTF8(data, len(data) < prescanLen) && !metaCharset(data) {
+		// nothing declared the encoding and the start of the body is ascii,
+		// or utf-8 cut off mid character. windows-1252 is only a guess at
+		// that point, so prefer utf-8, which is far more common and also a
+		// superset of ascii. an encoding from a <meta> tag is also reported
+		// as uncertain, so it is checked for.
+		e, name = encoding.Nop, "utf-8"
+	}
+
+	if e == encoding.Nop {
+		// the byte order mark isn't part of the text
+		if bytes.HasPrefix(data, utf8BOM) {
+			body.Discard(len(utf8BOM)) //nolint:errcheck
+		}
+		return body, name
+	}
+
+	// a byte order mark is only used to determine the encoding, the decoder
+	// mustn't pass it through
+	return transform.NewReader(body, unicode.BOMOverride(e.NewDecoder())), name
+}
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// isUTF8 reports whether data is valid utf-8. unless it is complete, it may end
+// with part of a character.
+func isUTF8(data []byte, complete bool) bool {
+	if !complete {
+		for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					data = data[:i]
+				}
+				break
+			}
+		}
+	}
+	return utf8.Valid(data)
+}
+
+// metaCharset reports whether the start of the body declares a known encoding
+// with a <meta charset> or <meta http-equiv="Content-Type"> tag
+func metaCharset(data []byte) bool {
+	z := html.NewTokenizer(bytes.NewReader(data))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return false
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, more := z.TagName()
+			if string(name) != "meta" {
+				continue
+			}
+
+			for more {
+				var key, val []byte
+				key, val, more = z.TagAttr()
+
+				cs := string(val)
+				switch string(key) {
+				case "charset":
+				case "content":
+					_, params, err := mime.ParseMediaType(cs)
+					if err != nil {
+						continue
+					}
+					cs = params["charset"]
+				default:
+					continue
+				}
+
+				if e, _ := charset.Lookup(cs); e != nil {
+					return true
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package extract
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	// enough ascii that the prescan can't see past it
+	padding := "<!-- " + strings.Repeat("x", prescanLen) + " -->"
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		mediaType   string
+		encoding    string
+		want        string
+	}{{
+		name:        "utf-8",
+		body:        "café",
+		contentType: "text/html",
+		mediaType:   "text/html",
+		encoding:    "utf-8",
+		want:        "café",
+	}, {
+		name:        "header",
+		body:        "caf\xe9",
+		contentType: "text/html; charset=iso-8859-1",
+		mediaType:   "text/html",
+		encoding:    "windows-1252",
+		want:        "café",
+	}, {
+		name:        "meta charset",
+		body:        `<meta charset="iso-8859-1"><p>caf` + "\xe9",
+		contentType: "text/html",
+		mediaType:   "text/html",
+		encoding:    "windows-1252",
+		want:        `<meta charset="iso-8859-1"><p>café`,
+	}, {
+		name:        "meta charset after ascii",
+		body:        `<meta charset="iso-8859-1">` + padding + "caf\xe9",
+		contentType: "text/html",
+		mediaType:   "text/html",
+		encoding:    "windows-1252",
+		want:        `<meta charset="iso-8859-1">` + padding + "café",
+	}, {
+		name:        "meta http-equiv",
+		body:        `<meta http-equiv="Content-Type" content="text/html; charset=shift_jis">` + padding + "\x93\xfa\x96\x7b",
+		contentType: "text/html",
+		mediaType:   "text/html",
+		encoding:    "shift_jis",
+		want:        `<meta http-equiv="Content-Type" content="text/html; charset=shift_jis">` + padding + "日本",
+	}, {
+		name:        "utf-8 after ascii",
+		body:        padding + "café",
+		contentType: "text/html",
+		mediaType:   "text/html",
+		encoding:    "utf-8",
+		want:        padding + "café",
+	}, {
+		name:        "undeclared latin-1",
+		body:        "caf\xe9",
+		contentType: "text/plain",
+		mediaType:   "text/plain",
+		encoding:    "windows-1252",
+		want:        "café",
+	}, {
+		name:        "byte order mark",
+		body:        "\xff\xfec\x00a\x00f\x00\xe9\x00",
+		contentType: "text/plain; charset=iso-8859-1",
+		mediaType:   "text/plain",
+		encoding:    "utf-16le",
+		want:        "café",
+	}, {
+		name:        "binary",
+		body:        "%PDF-\xe9",
+		contentType: "application/pdf; charset=iso-8859-1",
+		mediaType:   "application/pdf",
+		encoding:    "",
+		want:        "%PDF-\xe9",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, encoding := Decode(bufio.NewReader(strings.NewReader(tt.body)), tt.contentType, tt.mediaType)
+			if encoding != tt.encoding {
+				t.Errorf("got encoding %q, want %q", encoding, tt.encoding)
+			}
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add([]byte(`<meta charset="iso-8859-1">caf`+"\xe9"), "text/html")
+	f.Add([]byte("caf\xc3\xa9"), "text/plain; charset=utf-8")
+	f.Add([]byte("\xfe\xff\x00c"), "text/plain")
+
+	f.Fuzz(func(t *testing.T, body []byte, contentType string) {
+		r, _ := Decode(bufio.NewReader(strings.NewReader(string(body))), contentType, "text/html")
+		if _, err := io.ReadAll(r); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package extract
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/robots"
+	"github.com/joshuarubin/brightwave-google/internal/scope"
+)
+
+// ErrUnsupported is returned when a document uses a feature the extractor
+// can't handle, e.g. an encrypted pdf
+var ErrUnsupported = errors.New("unsupported document")
+
+// ErrMalformed is returned when a document can't be parsed
+var ErrMalformed = errors.New("malformed document")
+
+// Document is the text, by field, and links extracted from a fetched page
+type Document struct {
+	Title       string
+	Description string
+	Headings    string
+	Body        []byte
+	Links       []index.Link
+
+	// the url the document says it should be indexed under, if any
+	Canonical *url.URL
+
+	// the directives from the document itself, e.g. robots meta tags
+	Robots robots.Directives
+}
+
+// Page returns the index page for the document. if the document has a
+// canonical url, the page is indexed under it and the url it was found at is
+// recorded as an alias. canonical urls on other sites are ignored, otherwise
+// any page could replace the pages of another site.
+func (d *Document) Page(p index.Page) index.Page {
+	if d.Canonical != nil && scope.SameDomain(*d.Canonical, p.URL) {
+		if u := index.CleanURL(d.Canonical); u.String() != p.URL.String() {
+			p.Aliases = append(slices.Clone(p.Aliases), p.URL)
+			p.URL = *u
+		}
+	}
+
+	p.Title = collapse(d.Title)
+	p.Description = collapse(d.Description)
+	p.Headings = collapse(d.Headings)
+	p.Links = d.Links
+	if d.Robots.NoFollow {
+		for i := range p.Links {
+			p.Links[i].NoFollow = true
+		}
+	}
+	return p
+}
+
+// Extractor extracts a document from the body of a page found at u
+type Extractor interface {
+	Extract(r io.Reader, u *url.URL) (*Document, error)
+}
+
+// ExtractorFunc is an Extractor implemented by a function
+type ExtractorFunc func(r io.Reader, u *url.URL) (*Document, error)
+
+func (fn ExtractorFunc) Extract(r io.Reader, u *url.URL) (*Document, error) {
+	return fn(r, u)
+}
+
+// Registry holds the extractor for each supported media type
+type Registry struct {
+	extractors map[string]Extractor
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		extractors: map[string]Extractor{},
+	}
+}
+
+// New returns a registry with the html, plain text and pdf extractors. agent
+// is the name of the crawler used to match robots meta tags.
+func New(agent string) *Registry {
+	r := NewRegistry()
+	r.Register(HTML{Agent: agent}, "text/html", "application/xhtml+xml")
+	r.Register(ExtractorFunc(Text), "text/plain")
+	r.Register(ExtractorFunc(PDF), "application/pdf")
+	return r
+}
+
+// Register the extractor for the media types, replacing any that was
+// registered for them before
+func (r *Registry) Register(e Extractor, mediaTypes ...string) {
+	for _, t := range mediaTypes {
+		r.extractors[strings.ToLower(t)] = e
+	}
+}
+
+// Get returns the extractor for the media type
+func (r *Registry) Get(mediaType string) (Extractor, bool) {
+	e, ok := r.extractors[strings.ToLower(mediaType)]
+	return e, ok
+}
+
+// sniffLen is the most bytes http.DetectContentType considers
+const sniffLen = 512
+
+// MediaType returns the media type of a body from the Content-Type header. if
+// it is missing or doesn't say what the body is, the media type is sniffed
+// from the start of the body, which isn't consumed.
+func MediaType(contentType string, body *bufio.Reader) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType != "application/octet-stream" {
+		return mediaType
+	}
+
+	data, err := body.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return "application/octet-stream"
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+
+	return mediaType
+}
+
+// Text extracts plain text documents, all of the text is the body
+func Text(r io.Reader, _ *url.URL) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		Body: data,
+	}, nil
+}
+
+// collapse runs of whitespace into a single space
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
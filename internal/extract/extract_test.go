@@ -0,0 +1,42 @@
+package extract
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/joshuarubin/brightwave-google/internal/index"
+)
+
+func TestDocumentPageCanonical(t *testing.T) {
+	tests := []struct {
+		page      string
+		canonical string
+		want      string
+	}{
+		{"https://example.com/a?utm=1", "https://example.com/a", "https://example.com/a"},
+		{"https://www.example.com/a", "https://example.com/a", "https://example.com/a"},
+		{"https://example.co.uk/a", "https://news.example.co.uk/a", "https://news.example.co.uk/a"},
+		{"https://example.com/a", "https://other.com/a", "https://example.com/a"},
+		{"https://a.co.uk/a", "https://b.co.uk/a", "https://a.co.uk/a"},
+		{"https://attacker.github.io/a", "https://victim.github.io/a", "https://attacker.github.io/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.page+" "+tt.canonical, func(t *testing.T) {
+			u, _ := url.Parse(tt.page)
+			canonical, _ := url.Parse(tt.canonical)
+
+			d := Document{Canonical: canonical}
+			p := d.Page(index.Page{URL: *u})
+
+			if got := p.URL.String(); got != tt.want {
+				t.Errorf("indexed under %s, want %s", got, tt.want)
+			}
+
+			aliased := len(p.Aliases) > 0
+			if want := tt.want != tt.page; aliased != want {
+				t.Errorf("got aliases %v", p.Aliases)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/robots"
+)
+
+// HTML extracts the text, by field, and links from html documents
+type HTML struct {
+	// the robots meta tags named after this agent apply in addition to those
+	// named robots
+	Agent string
+}
+
+func (e HTML) Extract(r io.Reader, u *url.URL) (*Document, error) {
+	z := html.NewTokenizer(r)
+	doc := newDocument(e.Agent, u)
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return doc.document(), nil
+			}
+			return nil, z.Err()
+		case html.TextToken:
+			doc.text(z.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			doc.start(t, tt == html.SelfClosingTagToken)
+			if t.Data != "a" {
+				continue
+			}
+
+			link, ok := doc.resolve(attr(t, "href"))
+			if !ok {
+				continue
+			}
+
+			// the anchor text is indexed for the link even if it won't be
+			// crawled
+			doc.link(*link, hasRel(attr(t, "rel"), "nofollow"))
+		case html.EndTagToken:
+			doc.end(z.Token())
+		}
+	}
+}
+
+// document is the text extracted from an html page, by field
+type document struct {
+	agent       string
+	title       bytes.Buffer
+	description string
+	headings    bytes.Buffer
+	body        bytes.Buffer
+	links       []index.Link
+
+	// the directives from robots meta tags
+	robots robots.Directives
+
+	// the url that relative links are resolved against, the page url unless
+	// the document has a <base href>
+	base    *url.URL
+	hasBase bool
+
+	// the url the page says it should be indexed under, from
+	// <link rel="canonical">, the first one found is used
+	canonical *url.URL
+
+	// the open elements containing the current token
+	tags []string
+
+	// the index in links of the anchor containing the current token, or -1
+	anchor int
+}
+
+func newDocument(agent string, u *url.URL) *document {
+	return &document{
+		agent:  agent,
+		base:   u,
+		anchor: -1,
+	}
+}
+
+// voidElements never have an end tag, so they aren't pushed onto the stack of
+// open elements
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+var headingElements = []string{"h1", "h2", "h3", "h4", "h5", "h6"}
+
+func (d *document) start(t html.Token, selfClosing bool) {
+	if !selfClosing && !voidElements[t.Data] {
+		d.tags = append(d.tags, t.Data)
+	}
+
+	switch t.Data {
+	case "meta":
+		switch name := attr(t, "name"); {
+		case strings.EqualFold(name, "description"):
+			d.description = attr(t, "content")
+		case robots.MetaApplies(d.agent, name):
+			d.robots = d.robots.Merge(robots.ParseDirectives(attr(t, "content")))
+		}
+	case "link":
+		if d.canonical == nil && isCanonical(attr(t, "rel")) {
+			if u, ok := canonicalURL(d.base, attr(t, "href")); ok {
+				d.canonical = u
+			}
+		}
+	case "base":
+		// only the first <base> with an href is used
+		if href, ok := hasAttr(t, "href"); ok && !d.hasBase {
+			if u, err := d.base.Parse(strings.TrimSpace(href)); err == nil {
+				d.base = u
+				d.hasBase = true
+			}
+		}
+	}
+}
+
+// resolve the href of a link against the document base. ok is false if the
+// link can't be crawled, i.e. it isn't http or https, or only refers to a
+// fragment of the page.
+func (d *document) resolve(href string) (*url.URL, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return nil, false
+	}
+
+	u, err := d.base.Parse(href)
+	if err != nil {
+		return nil, false
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		// e.g. mailto:, javascript: or tel:
+		return nil, false
+	}
+
+	if u.Host == "" {
+		return nil, false
+	}
+
+	return u, true
+}
+
+// end closes the most recently opened element with the same name, along with
+// any opened after it that weren't closed
+func (d *document) end(t html.Token) {
+	if t.Data == "a" {
+		d.anchor = -1
+	}
+
+	for i := len(d.tags) - 1; i >= 0; i-- {
+		if d.tags[i] == t.Data {
+			d.tags = d.tags[:i]
+			return
+		}
+	}
+}
+
+func (d *document) text(data []byte) {
+	if len(d.tags) == 0 {
+		return
+	}
+
+	switch d.tags[len(d.tags)-1] {
+	case "script", "style":
+		// don't consider these as text data
+		return
+	case "title":
+		d.title.Write(data)
+		d.title.WriteString(" ")
+		return
+	}
+
+	d.body.Write(data)
+	d.body.WriteString(" ")
+
+	if d.anchor >= 0 {
+		d.links[d.anchor].Text += string(data) + " "
+	}
+
+	if slices.ContainsFunc(d.tags, func(tag string) bool {
+		return slices.Contains(headingElements, tag)
+	}) {
+		d.headings.Write(data)
+		d.headings.WriteString(" ")
+	}
+}
+
+// link records a link whose anchor text is the text that follows until the
+// anchor is closed
+func (d *document) link(u url.URL, nofollow bool) {
+	d.anchor = len(d.links)
+	d.links = append(d.links, index.Link{
+		URL:      u,
+		NoFollow: nofollow,
+	})
+}
+
+func (d *document) document() *Document {
+	return &Document{
+		Title:       d.title.String(),
+		Description: d.description,
+		Headings:    d.headings.String(),
+		Body:        d.body.Bytes(),
+		Links:       d.links,
+		Canonical:   d.canonical,
+		Robots:      d.robots,
+	}
+}
+
+func attr(t html.Token, key string) string {
+	v, _ := hasAttr(t, key)
+	return v
+}
+
+// hasAttr returns the value of the attribute and whether it is present
+func hasAttr(t html.Token, key string) (string, bool) {
+	for _, a := range t.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,107 @@
+package extract
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joshuarubin/brightwave-google/internal/index"
+)
+
+func TestHTMLBase(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want []string
+	}{
+		{
+			"no base",
+			`<a href="b">b</a><a href="/c">c</a>`,
+			[]string{"https://example.com/dir/b", "https://example.com/c"},
+		},
+		{
+			"absolute base",
+			`<base href="https://cdn.example.com/other/"><a href="b">b</a>`,
+			[]string{"https://cdn.example.com/other/b"},
+		},
+		{
+			"relative base",
+			`<base href="../up/"><a href="b">b</a>`,
+			[]string{"https://example.com/up/b"},
+		},
+		{
+			"first base wins",
+			`<base target="_blank"><base href="/one/"><base href="/two/"><a href="b">b</a>`,
+			[]string{"https://example.com/one/b"},
+		},
+		{
+			"uncrawlable",
+			`<a href="#top">top</a><a href="mailto:a@example.com">mail</a><a href="javascript:void(0)">js</a>`,
+			nil,
+		},
+	}
+
+	u, _ := url.Parse("https://example.com/dir/page")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := HTML{}.Extract(strings.NewReader(tt.html), u)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, l := range doc.Links {
+				got = append(got, l.URL.String())
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got links %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLBaseCanonical(t *testing.T) {
+	u, _ := url.Parse("https://example.com/dir/page")
+
+	doc, err := HTML{}.Extract(strings.NewReader(`<base href="/one/"><link rel="canonical" href="c">`), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Canonical == nil || doc.Canonical.String() != "https://example.com/one/c" {
+		t.Errorf("got canonical %v, want it resolved against the base", doc.Canonical)
+	}
+}
+
+func TestHTMLFields(t *testing.T) {
+	u, _ := url.Parse("https://example.com/")
+
+	doc, err := HTML{}.Extract(strings.NewReader(`<html><head>
+<title>The title</title>
+<meta name="Description" content="The description">
+<script>var ignored;</script>
+</head><body>
+<h1>First <em>heading</em></h1>
+<p>Some text</p>
+<h3>Second</h3>
+</body></html>`), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := doc.Page(index.Page{URL: *u})
+
+	for _, tt := range []struct{ field, got, want string }{
+		{"title", p.Title, "The title"},
+		{"description", p.Description, "The description"},
+		{"headings", p.Headings, "First heading Second"},
+		{"body", strings.Join(strings.Fields(string(doc.Body)), " "), "First heading Some text Second"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("got %s %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,529 @@
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/joshuarubin/brightwave-google/internal/index"
+)
+
+// PDF extracts the text of pdf documents. the title comes from the document
+// information dictionary and links from the uri actions of link annotations.
+func PDF(r io.Reader, u *url.URL) (_ *Document, err error) {
+	// the parser is tolerant of damaged files, a panic on one that is
+	// malformed in a way it doesn't expect mustn't take the crawler down
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%w: %v", ErrMalformed, v)
+		}
+	}()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractPDF(data, u)
+}
+
+func extractPDF(data []byte, u *url.URL) (*Document, error) {
+	f, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := f.trailer["Encrypt"]; ok {
+		return nil, fmt.Errorf("%w: encrypted pdf", ErrUnsupported)
+	}
+
+	var (
+		doc  Document
+		body bytes.Buffer
+		seen = map[string]bool{}
+	)
+
+	if info := f.dict(f.trailer["Info"]); info != nil {
+		if title, ok := f.resolve(info["Title"]).(pdfString); ok {
+			doc.Title = pdfText(title)
+		}
+		if subject, ok := f.resolve(info["Subject"]).(pdfString); ok {
+			doc.Description = pdfText(subject)
+		}
+	}
+
+	pages := f.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("%w: pdf has no pages", ErrMalformed)
+	}
+
+	for _, page := range pages {
+		fonts := f.fonts(page.resources)
+		for _, content := range f.contents(page.dict) {
+			text := pdfContentText{fonts: fonts, w: &body}
+			text.run(content)
+			text.newline()
+		}
+
+		for _, link := range f.links(page.dict, u) {
+			if key := link.URL.String(); !seen[key] {
+				seen[key] = true
+				doc.Links = append(doc.Links, link)
+			}
+		}
+	}
+
+	doc.Body = body.Bytes()
+
+	return &doc, nil
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages returns the pages of the document in order
+func (f *pdfFile) pages() []pdfPage {
+	var pages []pdfPage
+
+	if root := f.dict(f.trailer["Root"]); root != nil {
+		f.walkPages(root["Pages"], nil, 0, map[pdfRef]bool{}, &pages)
+		if len(pages) > 0 {
+			return pages
+		}
+	}
+
+	// without a usable page tree, take every page object in the file
+	nums := make([]int64, 0, len(f.objects))
+	for num, obj := range f.objects {
+		if d, ok := obj.(pdfDict); ok && d["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	slices.Sort(nums)
+
+	for _, num := range nums {
+		d := f.objects[num].(pdfDict)
+		pages = append(pages, pdfPage{dict: d, resources: f.dict(d["Resources"])})
+	}
+
+	return pages
+}
+
+// walkPages adds the pages below node in the page tree. resources are
+// inherited from the ancestors of a page. seen holds the objects that have
+// been visited, as a malformed tree may refer to a node more than once.
+func (f *pdfFile) walkPages(node any, resources pdfDict, depth int, seen map[pdfRef]bool, pages *[]pdfPage) {
+	if ref, ok := node.(pdfRef); ok {
+		if seen[ref] {
+			return
+		}
+		seen[ref] = true
+	}
+
+	d := f.dict(node)
+	if d == nil || depth > maxPDFDepth || len(seen) > maxPDFPageNodes || len(*pages) >= maxPDFPageNodes {
+		return
+	}
+
+	if r := f.dict(d["Resources"]); r != nil {
+		resources = r
+	}
+
+	kids, ok := f.resolve(d["Kids"]).(pdfArray)
+	if !ok || d["Type"] == pdfName("Page") {
+		*pages = append(*pages, pdfPage{dict: d, resources: resources})
+		return
+	}
+
+	for _, kid := range kids {
+		f.walkPages(kid, resources, depth+1, seen, pages)
+	}
+}
+
+// contents returns the decoded content streams of a page
+func (f *pdfFile) contents(page pdfDict) [][]byte {
+	var streams []any
+	switch v := f.resolve(page["Contents"]).(type) {
+	case pdfStream:
+		streams = []any{v}
+	case pdfArray:
+		streams = v
+	}
+
+	var contents [][]byte
+	for _, s := range streams {
+		s, ok := f.resolve(s).(pdfStream)
+		if !ok {
+			continue
+		}
+		if data, err := f.decode(s); err == nil {
+			contents = append(contents, data)
+		}
+	}
+
+	return contents
+}
+
+// links returns the uris of the link annotations on a page
+func (f *pdfFile) links(page pdfDict, u *url.URL) []index.Link {
+	annots, ok := f.resolve(page["Annots"]).(pdfArray)
+	if !ok {
+		return nil
+	}
+
+	var links []index.Link
+	for _, a := range annots {
+		annot := f.dict(a)
+		if annot == nil || annot["Subtype"] != pdfName("Link") {
+			continue
+		}
+
+		action := f.dict(annot["A"])
+		if action == nil || action["S"] != pdfName("URI") {
+			continue
+		}
+
+		uri, ok := f.resolve(action["URI"]).(pdfString)
+		if !ok {
+			continue
+		}
+
+		target, err := u.Parse(strings.TrimSpace(string(uri)))
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			continue
+		}
+
+		links = append(links, index.Link{URL: *index.CleanURL(target)})
+	}
+
+	return links
+}
+
+// fonts returns the fonts of a page by resource name
+func (f *pdfFile) fonts(resources pdfDict) map[pdfName]*pdfFont {
+	fonts := map[pdfName]*pdfFont{}
+	if resources == nil {
+		return fonts
+	}
+
+	for name, v := range f.dict(resources["Font"]) {
+		if d := f.dict(v); d != nil {
+			fonts[name] = f.font(d)
+		}
+	}
+
+	return fonts
+}
+
+// pdfFont maps the character codes of a font to text
+type pdfFont struct {
+	// text for each code, from the ToUnicode cmap
+	cmap map[string]string
+
+	// the lengths, in bytes, of the codes in the cmap
+	lengths []int
+
+	// composite fonts use multibyte codes that can't be decoded without a cmap
+	composite bool
+}
+
+func (f *pdfFile) font(d pdfDict) *pdfFont {
+	font := pdfFont{
+		composite: d["Subtype"] == pdfName("Type0"),
+	}
+
+	if s, ok := f.resolve(d["ToUnicode"]).(pdfStream); ok {
+		if data, err := f.decode(s); err == nil {
+			font.parseCMap(data)
+		}
+	}
+
+	return &font
+}
+
+// parseCMap reads the bfchar and bfrange mappings of a ToUnicode cmap
+func (font *pdfFont) parseCMap(data []byte) {
+	font.cmap = map[string]string{}
+
+	add := func(code []byte, text string) {
+		font.cmap[string(code)] = text
+		if !slices.Contains(font.lengths, len(code)) {
+			font.lengths = append(font.lengths, len(code))
+		}
+	}
+
+	l := pdfLexer{data: data}
+	var operands []any
+	for {
+		obj, err := l.object()
+		if err != nil {
+			break
+		}
+
+		kw, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch kw {
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 && len(src) > 0 {
+					add(src, utf16Text(dst))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) == 0 || len(lo) != len(hi) || len(lo) > 4 {
+					continue
+				}
+
+				first, last := codeValue(lo), codeValue(hi)
+				if last < first || last-first > 0xffff {
+					continue
+				}
+
+				for k := range last - first + 1 {
+					src := codeBytes(first+k, len(lo))
+					switch dst := operands[i+2].(type) {
+					case pdfString:
+						// the last code unit is incremented for each code
+						units := utf16Units(dst)
+						if len(units) == 0 {
+							continue
+						}
+						units[len(units)-1] += uint16(k)
+						add(src, string(utf16.Decode(units)))
+					case pdfArray:
+						if j := int(k); j < len(dst) {
+							if s, ok := dst[j].(pdfString); ok {
+								add(src, utf16Text(s))
+							}
+						}
+					}
+				}
+			}
+		}
+
+		operands = operands[:0]
+	}
+
+	slices.Sort(font.lengths)
+}
+
+func codeValue(b []byte) uint32 {
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+	return v
+}
+
+func codeBytes(v uint32, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return b
+}
+
+// decode the text of a string shown with the font
+func (font *pdfFont) decode(s []byte) string {
+	if font == nil || font.cmap == nil {
+		if font != nil && font.composite {
+			return ""
+		}
+		return decode1252(s)
+	}
+
+	var b strings.Builder
+	for len(s) > 0 {
+		n := 0
+		for _, l := range font.lengths {
+			if l <= len(s) {
+				if text, ok := font.cmap[string(s[:l])]; ok {
+					b.WriteString(text)
+					n = l
+					break
+				}
+			}
+		}
+
+		if n == 0 {
+			// an unmapped code, skip it
+			n = 1
+			if font.composite {
+				n = 2
+			}
+		}
+
+		s = s[min(n, len(s)):]
+	}
+
+	return b.String()
+}
+
+func decode1252(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		b.WriteRune(charmap.Windows1252.DecodeByte(c))
+	}
+	return b.String()
+}
+
+func utf16Units(s []byte) []uint16 {
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return units
+}
+
+func utf16Text(s []byte) string {
+	return string(utf16.Decode(utf16Units(s)))
+}
+
+// pdfText decodes a text string, which is either utf-16 with a byte order mark
+// or, near enough, windows-1252
+func pdfText(s []byte) string {
+	if bytes.HasPrefix(s, []byte{0xfe, 0xff}) {
+		return utf16Text(s[2:])
+	}
+	if bytes.HasPrefix(s, []byte{0xef, 0xbb, 0xbf}) {
+		return string(s[3:])
+	}
+	return decode1252(s)
+}
+
+// pdfContentText writes the text shown by a content stream
+type pdfContentText struct {
+	fonts map[pdfName]*pdfFont
+	font  *pdfFont
+	w     *bytes.Buffer
+}
+
+// kerning adjustments in a TJ array, in thousandths of a unit of text space,
+// that move the next glyph further than this are taken to be a space
+const pdfSpaceAdjustment = -200
+
+func (t *pdfContentText) run(content []byte) {
+	l := pdfLexer{data: content}
+	var operands []any
+	for {
+		obj, err := l.object()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			// skip whatever couldn't be parsed
+			operands = operands[:0]
+			continue
+		}
+
+		kw, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch kw {
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					t.font = t.fonts[name]
+				}
+			}
+		case "Tj":
+			t.show(operands)
+		case "'", "\"":
+			t.newline()
+			t.show(operands)
+		case "TJ":
+			if len(operands) == 0 {
+				break
+			}
+			arr, _ := operands[len(operands)-1].(pdfArray)
+			for _, v := range arr {
+				switch v := v.(type) {
+				case pdfString:
+					t.w.WriteString(t.font.decode(v))
+				case float64:
+					if v < pdfSpaceAdjustment {
+						t.space()
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[1].(float64); ok && ty != 0 {
+					t.newline()
+					break
+				}
+			}
+			t.space()
+		case "T*", "Tm":
+			t.newline()
+		case "ET":
+			t.space()
+		case "ID":
+			l.skipInlineImage()
+		}
+
+		operands = operands[:0]
+	}
+}
+
+func (t *pdfContentText) show(operands []any) {
+	if len(operands) == 0 {
+		return
+	}
+	if s, ok := operands[len(operands)-1].(pdfString); ok {
+		t.w.WriteString(t.font.decode(s))
+	}
+}
+
+func (t *pdfContentText) space() {
+	if b := t.w.Bytes(); len(b) > 0 && !isPDFSpace(b[len(b)-1]) {
+		t.w.WriteByte(' ')
+	}
+}
+
+func (t *pdfContentText) newline() {
+	if b := t.w.Bytes(); len(b) > 0 && b[len(b)-1] != '\n' {
+		t.w.WriteByte('\n')
+	}
+}
+
+// skipInlineImage skips the data of an inline image, after its ID operator,
+// up to and including the EI operator that ends it
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos; i+1 < len(l.data); i++ {
+		if l.data[i] != 'E' || l.data[i+1] != 'I' {
+			continue
+		}
+		if i > 0 && !isPDFSpace(l.data[i-1]) {
+			continue
+		}
+		if i+2 < len(l.data) && !isPDFSpace(l.data[i+2]) && !isPDFDelim(l.data[i+2]) {
+			continue
+		}
+		l.pos = i + 2
+		return
+	}
+	l.pos = len(l.data)
+}
@@ -0,0 +1,224 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildPDF returns a pdf with the objects, numbered from 1, and a trailer
+// that refers to the first one as the catalog
+func buildPDF(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func stream(dict, data string) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func deflate(s string) string {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write([]byte(s))
+	w.Close()
+	return b.String()
+}
+
+func singlePage(content string) []byte {
+	return buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		content,
+	)
+}
+
+func TestPDF(t *testing.T) {
+	u, _ := url.Parse("https://example.com/doc.pdf")
+
+	tests := []struct {
+		name  string
+		data  []byte
+		title string
+		body  string
+		err   error
+	}{{
+		name: "text",
+		data: singlePage(stream("", "BT /F1 12 Tf (Hello) Tj 0 -14 Td (World) Tj ET")),
+		body: "Hello\nWorld",
+	}, {
+		name: "kerning",
+		data: singlePage(stream("", "BT [(Hel) 20 (lo) -500 (World)] TJ ET")),
+		body: "Hello World",
+	}, {
+		name: "flate",
+		data: singlePage(stream("/Filter /FlateDecode", deflate("BT (compressed) Tj ET"))),
+		body: "compressed",
+	}, {
+		name: "info",
+		data: append(singlePage(stream("", "BT (body) Tj ET")),
+			"5 0 obj\n<< /Title (A \\(Title\\)) >>\nendobj\ntrailer\n<< /Info 5 0 R >>\n"...),
+		title: "A (Title)",
+		body:  "body",
+	}, {
+		name: "not a pdf",
+		data: []byte("<html></html>"),
+		err:  ErrMalformed,
+	}, {
+		name: "no pages",
+		data: buildPDF("<< /Type /Catalog >>"),
+		err:  ErrMalformed,
+	}, {
+		name: "encrypted",
+		data: append(singlePage(stream("", "")), "trailer\n<< /Encrypt << >> >>\n"...),
+		err:  ErrUnsupported,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := PDF(bytes.NewReader(tt.data), u)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if doc.Title != tt.title {
+				t.Errorf("got title %q, want %q", doc.Title, tt.title)
+			}
+			if got := strings.TrimSpace(string(doc.Body)); got != tt.body {
+				t.Errorf("got body %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
+
+func TestPDFLinks(t *testing.T) {
+	u, _ := url.Parse("https://example.com/doc.pdf")
+
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Annots [4 0 R 5 0 R] >>",
+		"<< /Subtype /Link /A << /S /URI /URI (other.html) >> >>",
+		"<< /Subtype /Link /A << /S /URI /URI (mailto:someone@example.com) >> >>",
+	)
+
+	doc, err := PDF(bytes.NewReader(data), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Links) != 1 || doc.Links[0].URL.String() != "https://example.com/other.html" {
+		t.Errorf("got links %v", doc.Links)
+	}
+}
+
+// malformed files that used to panic
+func TestPDFMalformed(t *testing.T) {
+	u, _ := url.Parse("https://example.com/doc.pdf")
+
+	for _, data := range [][]byte{
+		singlePage("<< /Length +Inf >>\nstream\nBT (x) Tj ET\nendstream"),
+		singlePage("<< /Length 1e300 >>\nstream\nBT (x) Tj ET\nendstream"),
+		singlePage("<< /Length -5 >>\nstream\nBT (x) Tj ET\nendstream"),
+		append(singlePage(stream("", "")), stream("/Type /ObjStm /N 1 /First 4", "9 -5 << >>")...),
+		append(singlePage(stream("", "")), stream("/Type /ObjStm /N 1 /First +Inf", "9 0 << >>")...),
+		append(singlePage(stream("", "")), stream("/Type /ObjStm /N 1e300 /First 4", "9 0 << >>")...),
+	} {
+		if _, err := extractPDF(data, u); err != nil && !errors.Is(err, ErrMalformed) {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
+
+func TestPDFPageTreeCycle(t *testing.T) {
+	u, _ := url.Parse("https://example.com/doc.pdf")
+
+	// the pages node is its own kid, twice, which would recurse 2^64 times
+	// if nodes were visited more than once
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [2 0 R 3 0 R 2 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		stream("", "BT (Hello) Tj ET"),
+	)
+
+	done := make(chan *Document)
+	go func() {
+		doc, err := extractPDF(data, u)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- doc
+	}()
+
+	select {
+	case doc := <-done:
+		if doc == nil {
+			return
+		}
+		// the page is only extracted once
+		if got := strings.TrimSpace(string(doc.Body)); got != "Hello" {
+			t.Errorf("got body %q, want %q", got, "Hello")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("page tree walk didn't terminate")
+	}
+}
+
+func TestPDFDecodedLimit(t *testing.T) {
+	// each stream is within the limit, but together they exceed it
+	bomb := deflate(strings.Repeat(" ", maxPDFDecodedSize/2))
+
+	f, err := parsePDF(buildPDF(stream("/Filter /FlateDecode", bomb), stream("/Filter /FlateDecode", bomb), stream("/Filter /FlateDecode", bomb)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded int
+	for num := int64(1); num <= 3; num++ {
+		if data, err := f.decode(f.objects[num].(pdfStream)); err == nil {
+			decoded += len(data)
+		}
+	}
+
+	if decoded > maxPDFDecodedSize {
+		t.Errorf("decoded %d bytes, more than the limit of %d", decoded, maxPDFDecodedSize)
+	}
+}
+
+func FuzzPDF(f *testing.F) {
+	f.Add(singlePage(stream("", "BT /F1 12 Tf (Hello) Tj ET")))
+	f.Add(singlePage(stream("/Filter /FlateDecode", deflate("BT [(a) -300 (b)] TJ ET"))))
+	f.Add(singlePage("<< /Length +Inf >>\nstream\nBT (x) Tj ET\nendstream"))
+	f.Add(append(singlePage(stream("", "")), stream("/Type /ObjStm /N 1 /First 4", "9 -5 << >>")...))
+
+	u, _ := url.Parse("https://example.com/doc.pdf")
+
+	// extractPDF is called directly, so a panic isn't hidden by the recover in
+	// PDF
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc, err := extractPDF(data, u)
+		if err != nil {
+			if !errors.Is(err, ErrMalformed) && !errors.Is(err, ErrUnsupported) {
+				t.Errorf("unexpected error %v", err)
+			}
+			return
+		}
+		if doc == nil {
+			t.Error("nil document without an error")
+		}
+	})
+}
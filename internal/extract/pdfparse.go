@@ -0,0 +1,638 @@
+package extract
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// the pdf object model, as described in section 7.3 of ISO 32000
+type (
+	pdfName    string
+	pdfString  []byte
+	pdfDict    map[pdfName]any
+	pdfArray   []any
+	pdfKeyword string // operators, object delimiters and other bare words
+
+	pdfRef struct {
+		num int64
+		gen int64
+	}
+
+	// pdfStream holds the raw, still encoded, data of a stream
+	pdfStream struct {
+		dict pdfDict
+		data []byte
+	}
+)
+
+var errPDFSyntax = fmt.Errorf("%w: pdf syntax error", ErrMalformed)
+
+const (
+	// maxPDFDepth limits the nesting of arrays and dictionaries, and the
+	// length of reference chains, so malformed files can't recurse forever
+	maxPDFDepth = 64
+
+	// maxPDFDecodedSize limits the total size of the decoded streams of a
+	// document to protect against decompression bombs
+	maxPDFDecodedSize = 64 << 20
+
+	// maxPDFPageNodes limits the number of nodes of the page tree that are
+	// visited, and so the number of pages, of a document
+	maxPDFPageNodes = 10000
+)
+
+func isPDFSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isPDFDelim(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// pdfLexer splits pdf data into tokens and parses objects from them
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch c := l.data[l.pos]; {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			// comments run to the end of the line
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// word returns the regular characters starting at the current position
+func (l *pdfLexer) word() []byte {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	return l.data[start:l.pos]
+}
+
+// token returns the next token, ok is false at the end of the data
+func (l *pdfLexer) token() (any, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+
+	switch c := l.data[l.pos]; {
+	case c == '/':
+		l.pos++
+		return l.name(), true
+	case c == '(':
+		l.pos++
+		return l.literal(), true
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<"), true
+		}
+		l.pos++
+		return l.hex(), true
+	case c == '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return pdfKeyword(">>"), true
+		}
+		l.pos++
+		return pdfKeyword(">"), true
+	case isPDFDelim(c):
+		// [, ], {, } and an unbalanced )
+		l.pos++
+		return pdfKeyword(c), true
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		w := l.word()
+		// ParseFloat also accepts inf and nan, which aren't pdf numbers
+		if n, err := strconv.ParseFloat(string(w), 64); err == nil && !math.IsInf(n, 0) && !math.IsNaN(n) {
+			return n, true
+		}
+		return pdfKeyword(w), true
+	default:
+		return pdfKeyword(l.word()), true
+	}
+}
+
+// name reads a name, after its leading /, decoding #xx escapes
+func (l *pdfLexer) name() pdfName {
+	w := l.word()
+	if bytes.IndexByte(w, '#') < 0 {
+		return pdfName(w)
+	}
+
+	var b []byte
+	for i := 0; i < len(w); i++ {
+		if w[i] == '#' && i+2 < len(w) {
+			if v, err := strconv.ParseUint(string(w[i+1:i+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, w[i])
+	}
+	return pdfName(b)
+}
+
+// literal reads a literal string, after its opening parenthesis
+func (l *pdfLexer) literal() pdfString {
+	var (
+		b     []byte
+		depth = 1
+	)
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return b
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b
+			}
+			c = l.data[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// an escaped end of line continues the string on the next line
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					v := int(c - '0')
+					for range 2 {
+						if l.pos >= len(l.data) || l.data[l.pos] < '0' || l.data[l.pos] > '7' {
+							break
+						}
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				}
+			}
+		}
+
+		b = append(b, c)
+	}
+
+	return b
+}
+
+// hex reads a hexadecimal string, after its opening <
+func (l *pdfLexer) hex() pdfString {
+	var digits []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		if !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+
+	if len(digits)%2 == 1 {
+		// a missing final digit is assumed to be 0
+		digits = append(digits, '0')
+	}
+
+	b := make([]byte, hex.DecodedLen(len(digits)))
+	n, _ := hex.Decode(b, digits)
+	return b[:n]
+}
+
+// object parses the next object. keywords that aren't part of an object, such
+// as operators or the end of an array, are returned as a pdfKeyword.
+func (l *pdfLexer) object() (any, error) {
+	tok, ok := l.token()
+	if !ok {
+		return nil, io.EOF
+	}
+
+	switch t := tok.(type) {
+	case pdfKeyword:
+		switch t {
+		case "[":
+			return l.array()
+		case "<<":
+			return l.dict()
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return t, nil
+	case float64:
+		// an integer may be the start of an indirect reference, "num gen R"
+		save := l.pos
+		if gen, ok := l.token(); ok {
+			if g, ok := gen.(float64); ok {
+				if r, ok := l.token(); ok && r == pdfKeyword("R") {
+					return pdfRef{num: int64(t), gen: int64(g)}, nil
+				}
+			}
+		}
+		l.pos = save
+		return t, nil
+	default:
+		return tok, nil
+	}
+}
+
+func (l *pdfLexer) array() (pdfArray, error) {
+	if l.depth++; l.depth > maxPDFDepth {
+		return nil, fmt.Errorf("%w: nested too deeply", errPDFSyntax)
+	}
+	defer func() { l.depth-- }()
+
+	var arr pdfArray
+	for {
+		v, err := l.object()
+		if err != nil {
+			return nil, err
+		}
+		if v == pdfKeyword("]") {
+			return arr, nil
+		}
+		arr = append(arr, v)
+	}
+}
+
+func (l *pdfLexer) dict() (pdfDict, error) {
+	if l.depth++; l.depth > maxPDFDepth {
+		return nil, fmt.Errorf("%w: nested too deeply", errPDFSyntax)
+	}
+	defer func() { l.depth-- }()
+
+	d := pdfDict{}
+	for {
+		k, err := l.object()
+		if err != nil {
+			return nil, err
+		}
+		if k == pdfKeyword(">>") {
+			return d, nil
+		}
+
+		key, ok := k.(pdfName)
+		if !ok {
+			return nil, fmt.Errorf("%w: dictionary key is %T", errPDFSyntax, k)
+		}
+
+		v, err := l.object()
+		if err != nil {
+			return nil, err
+		}
+		if v == pdfKeyword(">>") {
+			// a key without a value
+			return d, nil
+		}
+		d[key] = v
+	}
+}
+
+// stream reads the data of a stream, after the stream keyword
+func (l *pdfLexer) stream(dict pdfDict) []byte {
+	// the keyword is followed by a single end of line
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	start := l.pos
+
+	if n, ok := pdfInt(dict["Length"], len(l.data)-start); ok {
+		end := start + n
+		l.pos = end
+		l.skipSpace()
+		if bytes.HasPrefix(l.data[l.pos:], []byte("endstream")) {
+			l.pos += len("endstream")
+			return l.data[start:end]
+		}
+	}
+
+	// the length is missing, indirect or wrong, so look for the end instead
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return l.data[start:]
+	}
+	l.pos = start + end + len("endstream")
+
+	return bytes.TrimRight(l.data[start:start+end], "\r\n")
+}
+
+// pdfInt returns a number as an int if it is in [0, max]
+func pdfInt(v any, max int) (int, bool) {
+	n, ok := v.(float64)
+	if !ok || !(n >= 0 && n <= float64(max)) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// pdfFile is the objects of a pdf file, by object number
+type pdfFile struct {
+	objects map[int64]any
+	trailer pdfDict
+
+	// the total size of the streams decoded so far
+	decoded int
+}
+
+var pdfObjectRe = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// parsePDF finds every object in the file by scanning for them, rather than
+// using the cross-reference table, so that damaged files can still be read.
+// objects defined later in the file, by incremental updates, replace earlier
+// ones.
+func parsePDF(data []byte) (*pdfFile, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: missing header", errPDFSyntax)
+	}
+
+	f := pdfFile{
+		objects: map[int64]any{},
+		trailer: pdfDict{},
+	}
+
+	var next int
+	for _, m := range pdfObjectRe.FindAllSubmatchIndex(data, -1) {
+		if m[0] < next || (m[0] > 0 && !isPDFSpace(data[m[0]-1])) {
+			// inside the previous object, e.g. in the data of a stream
+			continue
+		}
+
+		num, err := strconv.ParseInt(string(data[m[2]:m[3]]), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		l := pdfLexer{data: data, pos: m[1]}
+		obj, err := l.object()
+		if err != nil {
+			continue
+		}
+
+		if dict, ok := obj.(pdfDict); ok {
+			save := l.pos
+			if kw, ok := l.token(); ok && kw == pdfKeyword("stream") {
+				obj = pdfStream{dict: dict, data: l.stream(dict)}
+			} else {
+				l.pos = save
+			}
+		}
+
+		f.objects[num] = obj
+		next = l.pos
+	}
+
+	// the trailer is either after the cross-reference table or, since pdf 1.5,
+	// in the dictionary of the cross-reference stream
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("trailer"))
+		if j < 0 {
+			break
+		}
+		l := pdfLexer{data: data, pos: i + j + len("trailer")}
+		if d, err := l.object(); err == nil {
+			if d, ok := d.(pdfDict); ok {
+				f.addTrailer(d)
+			}
+		}
+		i += j + len("trailer")
+	}
+
+	nums := make([]int64, 0, len(f.objects))
+	for num := range f.objects {
+		nums = append(nums, num)
+	}
+	slices.Sort(nums)
+
+	for _, num := range nums {
+		s, ok := f.objects[num].(pdfStream)
+		if !ok {
+			continue
+		}
+		switch s.dict["Type"] {
+		case pdfName("XRef"):
+			f.addTrailer(s.dict)
+		case pdfName("ObjStm"):
+			f.loadObjectStream(s)
+		}
+	}
+
+	return &f, nil
+}
+
+func (f *pdfFile) addTrailer(d pdfDict) {
+	for _, k := range []pdfName{"Root", "Info", "Encrypt"} {
+		if v, ok := d[k]; ok {
+			f.trailer[k] = v
+		}
+	}
+}
+
+// loadObjectStream adds the objects compressed in an object stream, unless
+// they are defined outside of it
+func (f *pdfFile) loadObjectStream(s pdfStream) {
+	data, err := f.decode(s)
+	if err != nil {
+		return
+	}
+
+	n, ok1 := pdfInt(f.resolve(s.dict["N"]), len(data))
+	first, ok2 := pdfInt(f.resolve(s.dict["First"]), len(data))
+	if !ok1 || !ok2 {
+		return
+	}
+
+	// the stream starts with pairs of object numbers and offsets
+	header := pdfLexer{data: data[:first]}
+	for range n {
+		num, ok1 := header.token()
+		off, ok2 := header.token()
+		if !ok1 || !ok2 {
+			return
+		}
+		num2, ok1 := num.(float64)
+		off2, ok2 := pdfInt(off, len(data)-first-1)
+		if !ok1 || !ok2 {
+			return
+		}
+
+		if _, ok := f.objects[int64(num2)]; ok {
+			continue
+		}
+
+		l := pdfLexer{data: data, pos: first + off2}
+		if obj, err := l.object(); err == nil {
+			f.objects[int64(num2)] = obj
+		}
+	}
+}
+
+// resolve follows indirect references to the object they refer to
+func (f *pdfFile) resolve(v any) any {
+	for range maxPDFDepth {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = f.objects[ref.num]
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(v any) pdfDict {
+	switch v := f.resolve(v).(type) {
+	case pdfDict:
+		return v
+	case pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+// decode the data of a stream by applying its filters
+func (f *pdfFile) decode(s pdfStream) ([]byte, error) {
+	var filters []any
+	switch v := f.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{v}
+	case pdfArray:
+		filters = v
+	}
+
+	var params []any
+	switch v := f.resolve(s.dict["DecodeParms"]).(type) {
+	case pdfDict:
+		params = []any{v}
+	case pdfArray:
+		params = v
+	}
+
+	data := s.data
+	for i, filter := range filters {
+		if i < len(params) {
+			if p := f.dict(params[i]); p != nil {
+				if pred, ok := f.resolve(p["Predictor"]).(float64); ok && pred > 1 {
+					return nil, fmt.Errorf("%w: predictor %v", ErrUnsupported, pred)
+				}
+			}
+		}
+
+		var err error
+		switch f.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflate(data, maxPDFDecodedSize-f.decoded)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			l := pdfLexer{data: data}
+			data = l.hex()
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("%w: filter %v", ErrUnsupported, filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if f.decoded += len(data); f.decoded > maxPDFDecodedSize {
+			return nil, fmt.Errorf("%w: decoded streams too large", ErrUnsupported)
+		}
+	}
+
+	return data, nil
+}
+
+// inflate decompresses at most max bytes of zlib data. some files omit the
+// zlib header or have a bad checksum, so whatever could be decompressed is
+// returned.
+func inflate(data []byte, max int) ([]byte, error) {
+	var r io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		r = zr
+	} else {
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+
+	out, err := io.ReadAll(io.LimitReader(r, int64(max)+1))
+	if len(out) > max {
+		return nil, fmt.Errorf("%w: decoded streams too large", ErrUnsupported)
+	}
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+
+	out := make([]byte, 4*len(data))
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return out[:n], nil
+}
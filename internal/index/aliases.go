@@ -0,0 +1,210 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+)
+
+// AddAliases records urls that redirect to, or have as their canonical url, an
+// already indexed page. if target is itself an alias, the aliases point at the
+// page it is an alias of.
+func (i *Index) AddAliases(ctx context.Context, target url.URL, aliases []url.URL) error {
+	i.db.Lock()
+	defer i.db.Unlock()
+
+	tx, err := i.db.SQL.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction for alias add: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	queries := i.db.WithTx(tx)
+
+	resolved, err := resolveAlias(ctx, queries, target.String())
+	if err != nil {
+		return err
+	}
+
+	if resolved != target.String() {
+		u, err := url.Parse(resolved)
+		if err != nil {
+			return fmt.Errorf("error parsing alias target: %w", err)
+		}
+		target = *u
+	}
+
+	targetID, err := queries.GetPageIDByURL(ctx, target.String())
+	if err != nil {
+		return fmt.Errorf("error getting alias target: %w", err)
+	}
+
+	if err = i.addAliases(ctx, queries, targetID, target, aliases); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing alias add transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddRedirect records urls that redirect to a target on another host. The
+// target is crawled separately, when its host is ready, so until then it is
+// added as a stub page.
+func (i *Index) AddRedirect(ctx context.Context, target url.URL, depth uint32, aliases []url.URL) error {
+	i.db.Lock()
+	defer i.db.Unlock()
+
+	tx, err := i.db.SQL.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction for redirect add: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	queries := i.db.WithTx(tx)
+
+	err = queries.InsertStubPage(ctx, db.InsertStubPageParams{
+		URL:   CleanURL(&target).String(),
+		Depth: int64(depth),
+	})
+	if err != nil {
+		return fmt.Errorf("error inserting stub page: %w", err)
+	}
+
+	targetID, err := queries.GetPageIDByURL(ctx, CleanURL(&target).String())
+	if err != nil {
+		return fmt.Errorf("error getting redirect target: %w", err)
+	}
+
+	if err = i.addAliases(ctx, queries, targetID, target, aliases); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing redirect add transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Resolve returns the url the page at u is indexed under, which is u unless it
+// is an alias
+func (i *Index) Resolve(ctx context.Context, u url.URL) (string, error) {
+	i.db.RLock()
+	defer i.db.RUnlock()
+
+	return resolveAlias(ctx, i.db.Queries, CleanURL(&u).String())
+}
+
+// addAliases points the aliases at the target page. any page that was
+// previously indexed, or linked to, under an alias is merged into the target
+// so that its links and origins are kept.
+func (i *Index) addAliases(ctx context.Context, queries *db.Queries, targetID int64, target url.URL, aliases []url.URL) error {
+	targetURL := CleanURL(&target).String()
+
+	// the target is now a page in its own right
+	if err := queries.DeleteAlias(ctx, targetURL); err != nil {
+		return fmt.Errorf("error deleting alias: %w", err)
+	}
+
+	// pages whose anchors changed, the target gains those of the aliases
+	var (
+		linked  []int64
+		deleted = map[int64]bool{}
+	)
+	for _, alias := range aliases {
+		aliasURL := CleanURL(&alias).String()
+		if aliasURL == targetURL {
+			continue
+		}
+
+		err := queries.InsertAlias(ctx, db.InsertAliasParams{
+			URL:       aliasURL,
+			TargetURL: targetURL,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting alias: %w", err)
+		}
+
+		// urls that redirected to the alias now redirect to the target
+		err = queries.RetargetAliases(ctx, db.RetargetAliasesParams{
+			NewTargetURL: targetURL,
+			OldTargetURL: aliasURL,
+		})
+		if err != nil {
+			return fmt.Errorf("error retargeting aliases: %w", err)
+		}
+
+		aliasID, err := queries.GetPageIDByURL(ctx, aliasURL)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			continue
+		case err != nil:
+			return fmt.Errorf("error getting aliased page: %w", err)
+		}
+
+		err = queries.MoveLinks(ctx, db.MoveLinksParams{
+			NewPageID: targetID,
+			OldPageID: aliasID,
+		})
+		if err != nil {
+			return fmt.Errorf("error moving links: %w", err)
+		}
+
+		err = queries.MoveOrigins(ctx, db.MoveOriginsParams{
+			NewPageID: targetID,
+			OldPageID: aliasID,
+		})
+		if err != nil {
+			return fmt.Errorf("error moving origins: %w", err)
+		}
+
+		// the links from the aliased page are replaced by those of the target
+		// when it is indexed, the pages they linked to lose their anchors
+		targets, err := queries.DeleteLinks(ctx, aliasID)
+		if err != nil {
+			return fmt.Errorf("error deleting links: %w", err)
+		}
+		linked = append(linked, targetID)
+		linked = append(linked, targets...)
+
+		// anything that couldn't be moved because the target already had it
+		// is deleted along with the page
+		if err = queries.DeletePage(ctx, aliasID); err != nil {
+			return fmt.Errorf("error deleting aliased page: %w", err)
+		}
+
+		deleted[aliasID] = true
+	}
+
+	slices.Sort(linked)
+	for _, pageID := range slices.Compact(linked) {
+		if deleted[pageID] {
+			continue
+		}
+		if err := i.indexAnchors(ctx, queries, pageID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveAlias returns the url the page at u is indexed under
+func resolveAlias(ctx context.Context, queries *db.Queries, u string) (string, error) {
+	target, err := queries.GetAliasTarget(ctx, u)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return u, nil
+	case err != nil:
+		return "", fmt.Errorf("error getting alias target: %w", err)
+	}
+	return target, nil
+}
@@ -56,7 +56,6 @@ func (i *Index) ShouldIndex(ctx context.Context, u url.URL, tx *sql.Tx) bool {
 	})
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
-		return true
 	case err != nil:
 		slog.Error("error checking if should index", "error", err, "url", u.String())
 		return true
@@ -64,28 +63,174 @@ func (i *Index) ShouldIndex(ctx context.Context, u url.URL, tx *sql.Tx) bool {
 		// TODO(jrubin) delete the page from the index
 		return false
 	}
+
+	// pages that couldn't be indexed aren't fetched again any sooner than
+	// those that were
+	skipped, err := queries.IsSkipped(ctx, db.IsSkippedParams{
+		URL:       u.String(),
+		CreatedAt: expiration,
+	})
+	if err != nil {
+		slog.Error("error checking if skipped", "error", err, "url", u.String())
+		return true
+	}
+
+	return skipped == 0
+}
+
+// Validators returns the ETag and Last-Modified headers from when the page at
+// u, or the page it is an alias of, was last indexed, they are empty if it
+// hasn't been
+func (i *Index) Validators(ctx context.Context, u url.URL) (etag, lastModified string) {
+	i.db.RLock()
+	defer i.db.RUnlock()
+
+	target, err := resolveAlias(ctx, i.db.Queries, u.String())
+	if err != nil {
+		slog.Error("error getting validators", "error", err, "url", u.String())
+		return "", ""
+	}
+
+	v, err := i.db.GetValidators(ctx, target)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", ""
+	case err != nil:
+		slog.Error("error getting validators", "error", err, "url", u.String())
+		return "", ""
+	}
+
+	return v.Etag, v.LastModified
+}
+
+// Touch marks the page at u, or the page it is an alias of, as indexed now,
+// without reindexing it, because it hasn't changed. the urls it links to are
+// returned so that they can be crawled again.
+func (i *Index) Touch(ctx context.Context, u url.URL) ([]url.URL, error) {
+	i.db.Lock()
+	defer i.db.Unlock()
+
+	target, err := resolveAlias(ctx, i.db.Queries, u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = i.db.TouchPage(ctx, target); err != nil {
+		return nil, fmt.Errorf("error touching page: %w", err)
+	}
+
+	links, err := i.db.ListFollowLinks(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("error listing links: %w", err)
+	}
+
+	ret := make([]url.URL, 0, len(links))
+	for _, link := range links {
+		l, err := url.Parse(link)
+		if err != nil {
+			slog.Warn("error parsing link", "error", err, "url", link)
+			continue
+		}
+		ret = append(ret, *l)
+	}
+
+	return ret, nil
+}
+
+// Skip records that the page at u was fetched but not indexed, because no
+// extractor supports its content type or extraction failed
+func (i *Index) Skip(ctx context.Context, u url.URL, contentType, reason string) error {
+	i.db.Lock()
+	defer i.db.Unlock()
+
+	err := i.db.InsertSkipped(ctx, db.InsertSkippedParams{
+		URL:         u.String(),
+		ContentType: contentType,
+		Reason:      reason,
+	})
+	if err != nil {
+		return fmt.Errorf("error inserting skipped: %w", err)
+	}
+
+	return nil
 }
 
 type Page struct {
-	URL    url.URL
-	Origin url.URL
-	Depth  uint32
+	URL         url.URL
+	Origin      url.URL
+	Depth       uint32
+	Title       string
+	Description string
+	Headings    string
+	Links       []Link
+
+	// urls that redirected to the page, or that have it as their canonical url
+	Aliases []url.URL
+
+	// validators from the response, used to only refetch the page if it has
+	// changed
+	ETag         string
+	LastModified string
+
+	// the body was cut off at the maximum size that is read
+	Truncated bool
+}
+
+// Fields that terms are indexed under
+const (
+	FieldBody        = "body"
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldHeadings    = "headings"
+
+	// FieldAnchor is the text of links to the page from other pages. it is
+	// maintained by the linking pages, so it is kept when the page itself is
+	// re-indexed.
+	FieldAnchor = "anchor"
+)
+
+// Fields are all of the fields that terms are indexed under
+var Fields = []string{FieldBody, FieldTitle, FieldDescription, FieldHeadings, FieldAnchor}
+
+// tokenize normalizes and tokenizes the data
+func tokenize(data []byte) ([]text.Term, error) {
+	data, err := text.Normalize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return text.Tokenize(data)
 }
 
 func (i *Index) Add(ctx context.Context, page Page, data []byte) error {
 	// normally, this should probably go into a processing queue/pipeline
 	// but for the purpose of this exercise, these operations are fast enough
 	// to do here
-	data, err := text.Normalize(data)
-	if err != nil {
-		return err
-	}
 
 	// TODO(jrubin) tokenize parts of url too
 
-	tokens, err := text.Tokenize(data)
+	fields := map[string][]text.Term{}
+	for field, data := range map[string][]byte{
+		FieldBody:        data,
+		FieldTitle:       []byte(page.Title),
+		FieldDescription: []byte(page.Description),
+		FieldHeadings:    []byte(page.Headings),
+	} {
+		terms, err := tokenize(data)
+		if err != nil {
+			return err
+		}
+		fields[field] = terms
+	}
+
+	var length int64
+	for _, t := range fields[FieldBody] {
+		length += int64(t.Count())
+	}
+
+	compressed, err := CompressText(data)
 	if err != nil {
-		return err
+		return fmt.Errorf("error compressing text: %w", err)
 	}
 
 	i.db.RLock()
@@ -102,7 +247,18 @@ func (i *Index) Add(ctx context.Context, page Page, data []byte) error {
 	if !i.ShouldIndex(ctx, page.URL, tx) {
 		i.db.RUnlock()
 		slog.Info("index.add: not re-indexing", "url", page.URL.String())
-		return nil
+
+		if len(page.Aliases) == 0 {
+			return nil
+		}
+
+		// the page was already indexed, e.g. it was found at another url with
+		// the same canonical url, but the urls it was found at this time still
+		// need to be recorded
+		if err = tx.Rollback(); err != nil {
+			return fmt.Errorf("error ending index add transaction: %w", err)
+		}
+		return i.AddAliases(ctx, page.URL, page.Aliases)
 	}
 
 	queries := i.db.WithTx(tx)
@@ -112,14 +268,30 @@ func (i *Index) Add(ctx context.Context, page Page, data []byte) error {
 	defer i.db.Unlock()
 
 	dbPage, err := queries.InsertPage(ctx, db.InsertPageParams{
-		URL:   page.URL.String(),
-		Depth: int64(page.Depth),
+		URL:          page.URL.String(),
+		Depth:        int64(page.Depth),
+		Length:       length,
+		Title:        page.Title,
+		Text:         compressed,
+		Description:  page.Description,
+		Headings:     page.Headings,
+		Etag:         page.ETag,
+		LastModified: page.LastModified,
+		Truncated:    page.Truncated,
 	})
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		dbPage, err = queries.UpdatePage(ctx, db.UpdatePageParams{
-			URL:   page.URL.String(),
-			Depth: int64(page.Depth),
+			URL:          page.URL.String(),
+			Depth:        int64(page.Depth),
+			Length:       length,
+			Title:        page.Title,
+			Text:         compressed,
+			Description:  page.Description,
+			Headings:     page.Headings,
+			Etag:         page.ETag,
+			LastModified: page.LastModified,
+			Truncated:    page.Truncated,
 		})
 		if err != nil {
 			return fmt.Errorf("error updating page: %w", err)
@@ -136,31 +308,35 @@ func (i *Index) Add(ctx context.Context, page Page, data []byte) error {
 		return fmt.Errorf("error inserting origin: %w", err)
 	}
 
+	// the page may have been skipped by a previous crawl
+	if err = queries.DeleteSkipped(ctx, page.URL.String()); err != nil {
+		return fmt.Errorf("error deleting skipped: %w", err)
+	}
+
+	// replace any postings from a previous index of the page, this happens
+	// within the transaction so searches never see a partially indexed page
+	if err = queries.DeletePageTerms(ctx, dbPage.ID); err != nil {
+		return fmt.Errorf("error deleting page terms: %w", err)
+	}
+
+	if err = queries.DeletePageFields(ctx, dbPage.ID); err != nil {
+		return fmt.Errorf("error deleting page fields: %w", err)
+	}
+
 	// sqlite supports multi-row inserts, but sqlc doesn't seem to support that
 	// yet, so we'll use inefficient one-row inserts
-LOOP:
-	for _, t := range tokens {
-		var term db.Term
-		term, err = queries.InsertTerm(ctx, t)
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			term, err = queries.GetTerm(ctx, t)
-			if err != nil {
-				slog.Warn("error getting existing term", "error", err, "term", t)
-				continue LOOP
-			}
-		case err != nil:
-			slog.Warn("error inserting term", "error", err, "term", t)
-			continue LOOP
+	for field, terms := range fields {
+		if err = i.insertField(ctx, queries, dbPage.ID, field, terms); err != nil {
+			return err
 		}
+	}
 
-		err = queries.InsertPageTerm(ctx, db.InsertPageTermParams{
-			PageID: dbPage.ID,
-			TermID: term.ID,
-		})
-		if err != nil {
-			slog.Warn("error inserting page term", "error", err, "pageID", dbPage.ID, "termID", term.ID, "term", t, "page", page.URL.String())
-		}
+	if err = i.addAliases(ctx, queries, dbPage.ID, page.URL, page.Aliases); err != nil {
+		return err
+	}
+
+	if err = i.updateLinks(ctx, queries, dbPage.ID, page); err != nil {
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -171,3 +347,55 @@ LOOP:
 
 	return nil
 }
+
+// insertField inserts the postings of the terms found in a field of the page
+// along with its length
+func (i *Index) insertField(ctx context.Context, queries *db.Queries, pageID int64, field string, terms []text.Term) error {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var length int64
+	for _, t := range terms {
+		length += int64(t.Count())
+		i.insertPageTerm(ctx, queries, pageID, field, t)
+	}
+
+	// the length of each field is needed to score its terms
+	err := queries.InsertPageField(ctx, db.InsertPageFieldParams{
+		PageID: pageID,
+		Field:  field,
+		Length: length,
+	})
+	if err != nil {
+		return fmt.Errorf("error inserting page field: %w", err)
+	}
+
+	return nil
+}
+
+func (i *Index) insertPageTerm(ctx context.Context, queries *db.Queries, pageID int64, field string, t text.Term) {
+	term, err := queries.InsertTerm(ctx, t.Term)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		term, err = queries.GetTerm(ctx, t.Term)
+		if err != nil {
+			slog.Warn("error getting existing term", "error", err, "term", t.Term)
+			return
+		}
+	case err != nil:
+		slog.Warn("error inserting term", "error", err, "term", t.Term)
+		return
+	}
+
+	err = queries.InsertPageTerm(ctx, db.InsertPageTermParams{
+		PageID:    pageID,
+		TermID:    term.ID,
+		Count:     int64(t.Count()),
+		Positions: EncodePositions(t.Positions),
+		Field:     field,
+	})
+	if err != nil {
+		slog.Warn("error inserting page term", "error", err, "pageID", pageID, "termID", term.ID, "term", t.Term, "field", field)
+	}
+}
@@ -0,0 +1,181 @@
+package index
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+)
+
+func newIndex(t *testing.T) *Index {
+	t.Helper()
+
+	d, err := db.Init(context.Background(), filepath.Join(t.TempDir(), "test.db"), func(int, string, string, int64) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SQL.Close() })
+
+	return New(d, time.Hour)
+}
+
+func mustParse(t *testing.T, raw string) url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *u
+}
+
+func TestShouldIndexSkipped(t *testing.T) {
+	ctx := context.Background()
+	i := newIndex(t)
+
+	u := mustParse(t, "https://example.com/image.png")
+
+	if !i.ShouldIndex(ctx, u, nil) {
+		t.Fatal("new url isn't indexed")
+	}
+
+	if err := i.Skip(ctx, u, "image/png", "unsupported content type"); err != nil {
+		t.Fatal(err)
+	}
+
+	if i.ShouldIndex(ctx, u, nil) {
+		t.Error("recently skipped url is fetched again")
+	}
+
+	// once the skip is older than the reindex duration, the url is fetched
+	// again in case its content type has changed
+	_, err := i.db.SQL.ExecContext(ctx, "UPDATE skipped SET created_at = ?", time.Now().UTC().Add(-2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !i.ShouldIndex(ctx, u, nil) {
+		t.Error("stale skipped url isn't fetched again")
+	}
+}
+
+func TestMergeAliasAnchors(t *testing.T) {
+	ctx := context.Background()
+	i := newIndex(t)
+
+	var (
+		origin    = mustParse(t, "https://example.com/")
+		alias     = mustParse(t, "https://example.com/alias")
+		canonical = mustParse(t, "https://example.com/canonical")
+		target    = mustParse(t, "https://example.com/target")
+	)
+
+	anchors := func() (terms int, length int64) {
+		t.Helper()
+
+		err := i.db.SQL.QueryRowContext(ctx, `
+SELECT
+    (SELECT COUNT(*) FROM page_terms WHERE page_id = p.id AND field = 'anchor'),
+    COALESCE((SELECT length FROM page_fields WHERE page_id = p.id AND field = 'anchor'), 0)
+FROM pages AS p
+WHERE url = ?`, target.String()).Scan(&terms, &length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return terms, length
+	}
+
+	// the alias was indexed as a page of its own before its canonical url was
+	// known
+	err := i.Add(ctx, Page{
+		URL:    alias,
+		Origin: origin,
+		Links:  []Link{{URL: target, Text: "zebra crossing"}},
+	}, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if terms, length := anchors(); terms != 2 || length != 2 {
+		t.Fatalf("got %d anchor terms of length %d, want 2 of length 2", terms, length)
+	}
+
+	err = i.Add(ctx, Page{
+		URL:     canonical,
+		Origin:  origin,
+		Aliases: []url.URL{alias},
+	}, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the link was on the aliased page, which was merged into one without it
+	if terms, length := anchors(); terms != 0 || length != 0 {
+		t.Errorf("got %d anchor terms of length %d from a deleted page", terms, length)
+	}
+}
+
+func TestReindexPostings(t *testing.T) {
+	ctx := context.Background()
+	i := newIndex(t)
+
+	u := mustParse(t, "https://example.com/")
+
+	counts := func() map[string]int64 {
+		t.Helper()
+
+		rows, err := i.db.SQL.QueryContext(ctx, `
+SELECT t.term, pt.count
+FROM page_terms AS pt
+JOIN terms AS t ON t.id = pt.term_id
+WHERE pt.field = 'body'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		ret := map[string]int64{}
+		for rows.Next() {
+			var (
+				term  string
+				count int64
+			)
+			if err = rows.Scan(&term, &count); err != nil {
+				t.Fatal(err)
+			}
+			ret[term] = count
+		}
+		if err = rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return ret
+	}
+
+	if err := i.Add(ctx, Page{URL: u, Origin: u}, []byte("zebra fox zebra zebra")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := counts(), map[string]int64{"zebra": 3, "fox": 1}; !maps.Equal(got, want) {
+		t.Errorf("got term counts %v, want %v", got, want)
+	}
+
+	// the page is due to be indexed again
+	if _, err := i.db.SQL.ExecContext(ctx, "UPDATE pages SET modified_at = ?", time.Now().UTC().Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the postings of the previous version of the page are replaced, rather
+	// than added to
+	if err := i.Add(ctx, Page{URL: u, Origin: u}, []byte("fox owl")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := counts(), map[string]int64{"fox": 1, "owl": 1}; !maps.Equal(got, want) {
+		t.Errorf("got term counts %v after reindexing, want %v", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/text"
+)
+
+// Link is a link found on a page along with its anchor text
+type Link struct {
+	URL      url.URL
+	Text     string
+	NoFollow bool // the link should not be crawled
+}
+
+// anchorGap is added between the positions of terms from different anchors to
+// the same page so that phrases don't match across them
+const anchorGap = 10
+
+// updateLinks replaces the links from the source page with its current links,
+// then rebuilds the anchor field of every page that was linked to
+// before or now. targets that haven't been indexed yet are added as stub pages
+// so that they can be found by their anchor text.
+func (i *Index) updateLinks(ctx context.Context, queries *db.Queries, sourceID int64, page Page) error {
+	targets, err := queries.DeleteLinks(ctx, sourceID)
+	if err != nil {
+		return fmt.Errorf("error deleting links: %w", err)
+	}
+
+	source := page.URL.String()
+
+	for _, l := range page.Links {
+		if l.URL.Scheme != "http" && l.URL.Scheme != "https" {
+			// only links to pages that can be crawled are indexed
+			continue
+		}
+
+		// links to urls that redirect are links to the page they redirect to
+		target, err := resolveAlias(ctx, queries, CleanURL(&l.URL).String())
+		if err != nil {
+			return err
+		}
+
+		if target == source {
+			continue
+		}
+
+		err = queries.InsertStubPage(ctx, db.InsertStubPageParams{
+			URL:   target,
+			Depth: int64(page.Depth) + 1,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting stub page: %w", err)
+		}
+
+		targetID, err := queries.GetPageIDByURL(ctx, target)
+		if err != nil {
+			return fmt.Errorf("error getting linked page: %w", err)
+		}
+
+		// the target was discovered by the same request as the source
+		err = queries.InsertOrigin(ctx, db.InsertOriginParams{
+			PageID: targetID,
+			Origin: page.Origin.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting origin: %w", err)
+		}
+
+		err = queries.InsertLink(ctx, db.InsertLinkParams{
+			SourcePageID: sourceID,
+			TargetPageID: targetID,
+			AnchorText:   strings.Join(strings.Fields(l.Text), " "),
+			Nofollow:     l.NoFollow,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting link: %w", err)
+		}
+
+		targets = append(targets, targetID)
+	}
+
+	slices.Sort(targets)
+	for _, targetID := range slices.Compact(targets) {
+		if err = i.indexAnchors(ctx, queries, targetID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexAnchors rebuilds the anchor field of the page from the text of every
+// anchor linking to it
+func (i *Index) indexAnchors(ctx context.Context, queries *db.Queries, pageID int64) error {
+	texts, err := queries.GetAnchorText(ctx, pageID)
+	if err != nil {
+		return fmt.Errorf("error getting anchor text: %w", err)
+	}
+
+	err = queries.DeleteFieldTerms(ctx, db.DeleteFieldTermsParams{
+		PageID: pageID,
+		Field:  FieldAnchor,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting anchor terms: %w", err)
+	}
+
+	err = queries.DeleteField(ctx, db.DeleteFieldParams{
+		PageID: pageID,
+		Field:  FieldAnchor,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting anchor field: %w", err)
+	}
+
+	terms, err := anchorTerms(texts)
+	if err != nil {
+		return err
+	}
+
+	return i.insertField(ctx, queries, pageID, FieldAnchor, terms)
+}
+
+// anchorTerms tokenizes the text of each anchor and merges their terms
+func anchorTerms(texts []string) ([]text.Term, error) {
+	var (
+		ret    []text.Term
+		terms  = map[string]int{}
+		offset uint32
+	)
+
+	for _, t := range texts {
+		tokens, err := tokenize([]byte(t))
+		if err != nil {
+			return nil, err
+		}
+
+		var last uint32
+		for _, tok := range tokens {
+			j, ok := terms[tok.Term]
+			if !ok {
+				j = len(ret)
+				terms[tok.Term] = j
+				ret = append(ret, text.Term{Term: tok.Term})
+			}
+			for _, p := range tok.Positions {
+				ret[j].Positions = append(ret[j].Positions, offset+p)
+				last = max(last, p)
+			}
+		}
+
+		offset += last + 1 + anchorGap
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,37 @@
+package index
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidPositions = errors.New("invalid positions encoding")
+
+// EncodePositions encodes ascending term positions as delta encoded uvarints
+func EncodePositions(positions []uint32) []byte {
+	ret := make([]byte, 0, len(positions))
+	var prev uint32
+	for _, p := range positions {
+		ret = binary.AppendUvarint(ret, uint64(p-prev))
+		prev = p
+	}
+	return ret
+}
+
+// DecodePositions decodes positions encoded with EncodePositions
+func DecodePositions(data []byte) ([]uint32, error) {
+	var (
+		ret  []uint32
+		prev uint32
+	)
+	for len(data) > 0 {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, ErrInvalidPositions
+		}
+		prev += uint32(delta)
+		ret = append(ret, prev)
+		data = data[n:]
+	}
+	return ret, nil
+}
@@ -0,0 +1,47 @@
+package index
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPositions(t *testing.T) {
+	for _, positions := range [][]uint32{
+		nil,
+		{0},
+		{0, 1, 2},
+		{3, 130, 20000, 1 << 31},
+	} {
+		got, err := DecodePositions(EncodePositions(positions))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, positions) {
+			t.Errorf("got %v, want %v", got, positions)
+		}
+	}
+
+	if _, err := DecodePositions([]byte{0x80}); !errors.Is(err, ErrInvalidPositions) {
+		t.Errorf("got error %v, want %v", err, ErrInvalidPositions)
+	}
+}
+
+func FuzzDecodePositions(f *testing.F) {
+	f.Add(EncodePositions([]uint32{1, 5, 300}))
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		positions, err := DecodePositions(data)
+		if err != nil {
+			return
+		}
+
+		// whatever decodes round trips, deltas wrap around the same way in
+		// both directions
+		got, err := DecodePositions(EncodePositions(positions))
+		if err != nil || !slices.Equal(got, positions) {
+			t.Errorf("got %v %v, want %v", got, err, positions)
+		}
+	})
+}
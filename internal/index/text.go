@@ -0,0 +1,40 @@
+package index
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// CompressText collapses the whitespace in the extracted text of a page and
+// compresses it for storage
+func CompressText(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(bytes.Join(bytes.Fields(data), []byte(" "))); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecompressText returns the text of a page compressed with CompressText
+func DecompressText(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		// the page was indexed before its text was stored
+		return nil, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
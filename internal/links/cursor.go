@@ -0,0 +1,30 @@
+package links
+
+import (
+	"github.com/joshuarubin/brightwave-google/internal/pagetoken"
+)
+
+// cursor is the position in the links of a page to continue from on the next
+// page. links are ordered by the url of the page at their other end, then by
+// their anchor text, which identify a link however many times its page is
+// re-indexed. links found while the links are being walked are returned if
+// they sort after the cursor.
+type cursor struct {
+	URL  string `json:"u"`
+	Text string `json:"t"`
+}
+
+// encode the cursor as a page token for the links of the page at u
+func (c cursor) encode(u string) (string, error) {
+	return pagetoken.Encode(u, c)
+}
+
+// decodeCursor decodes a page token, which must have been created for the same
+// url
+func decodeCursor(token, u string) (cursor, error) {
+	var c cursor
+	if err := pagetoken.Decode(token, u, &c); err != nil {
+		return cursor{}, err
+	}
+	return c, nil
+}
@@ -0,0 +1,175 @@
+package links
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
+)
+
+const (
+	// DefaultPageSize is the number of links returned when the request doesn't
+	// specify a page size
+	DefaultPageSize = 100
+
+	// MaxPageSize is the largest number of links that can be returned at once
+	MaxPageSize = 1000
+)
+
+// Links answers queries about the link graph
+type Links struct {
+	db *db.DB
+}
+
+func New(d *db.DB) *Links {
+	return &Links{
+		db: d,
+	}
+}
+
+// request is the part common to the outlinks and backlinks requests
+type request interface {
+	GetUrl() string
+	GetPageSize() uint32
+	GetPageToken() string
+}
+
+// lister returns up to limit links to or from the page, after the cursor
+type lister func(ctx context.Context, pageID int64, after cursor, limit int64) ([]db.GetOutlinksRow, error)
+
+// Outlinks returns the links found on a page, ordered by their target
+func (l *Links) Outlinks(ctx context.Context, req *pb.GetOutlinksRequest) (*pb.GetOutlinksResponse, error) {
+	links, next, err := l.list(ctx, req, func(ctx context.Context, pageID int64, after cursor, limit int64) ([]db.GetOutlinksRow, error) {
+		return l.db.GetOutlinks(ctx, db.GetOutlinksParams{
+			SourcePageID: pageID,
+			AfterURL:     after.URL,
+			AfterText:    after.Text,
+			Limit:        limit,
+		})
+	}, func(row db.GetOutlinksRow) string {
+		return row.TargetURL
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOutlinksResponse{
+		Links:         links,
+		NextPageToken: next,
+	}, nil
+}
+
+// Backlinks returns the links to a page from other pages, ordered by their
+// source
+func (l *Links) Backlinks(ctx context.Context, req *pb.GetBacklinksRequest) (*pb.GetBacklinksResponse, error) {
+	links, next, err := l.list(ctx, req, func(ctx context.Context, pageID int64, after cursor, limit int64) ([]db.GetOutlinksRow, error) {
+		rows, err := l.db.GetBacklinks(ctx, db.GetBacklinksParams{
+			TargetPageID: pageID,
+			AfterURL:     after.URL,
+			AfterText:    after.Text,
+			Limit:        limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ret := make([]db.GetOutlinksRow, len(rows))
+		for i, row := range rows {
+			ret[i] = db.GetOutlinksRow(row)
+		}
+
+		return ret, nil
+	}, func(row db.GetOutlinksRow) string {
+		return row.SourceURL
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetBacklinksResponse{
+		Links:         links,
+		NextPageToken: next,
+	}, nil
+}
+
+// list returns a page of the links listed by fn. other is the url of the page
+// at the other end of a link, which links are ordered by.
+func (l *Links) list(ctx context.Context, req request, fn lister, other func(db.GetOutlinksRow) string) ([]*pb.Link, string, error) {
+	u, err := url.Parse(req.GetUrl())
+	if err != nil || !u.IsAbs() {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid url: %q", req.GetUrl())
+	}
+	pageURL := index.CleanURL(u).String()
+
+	pageSize := int64(req.GetPageSize())
+	switch {
+	case pageSize == 0:
+		pageSize = DefaultPageSize
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	}
+
+	var cur cursor
+	if token := req.GetPageToken(); token != "" {
+		if cur, err = decodeCursor(token, pageURL); err != nil {
+			return nil, "", status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+
+	l.db.RLock()
+	defer l.db.RUnlock()
+
+	// urls that redirect are resolved to the page they redirect to
+	target, err := l.db.GetAliasTarget(ctx, pageURL)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		target = pageURL
+	case err != nil:
+		return nil, "", status.Errorf(codes.Internal, "error getting alias: %v", err)
+	}
+
+	pageID, err := l.db.GetPageIDByURL(ctx, target)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, "", status.Errorf(codes.NotFound, "page not found: %s", target)
+	case err != nil:
+		return nil, "", status.Errorf(codes.Internal, "error getting page: %v", err)
+	}
+
+	// get one more than requested to know if there is another page
+	rows, err := fn(ctx, pageID, cur, pageSize+1)
+	if err != nil {
+		return nil, "", status.Errorf(codes.Internal, "error listing links: %v", err)
+	}
+
+	var next string
+	if int64(len(rows)) > pageSize {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		next, err = cursor{
+			URL:  other(last),
+			Text: last.AnchorText,
+		}.encode(pageURL)
+		if err != nil {
+			return nil, "", status.Errorf(codes.Internal, "error encoding page token: %v", err)
+		}
+	}
+
+	links := make([]*pb.Link, len(rows))
+	for i, row := range rows {
+		links[i] = &pb.Link{
+			SourceUrl:  row.SourceURL,
+			TargetUrl:  row.TargetURL,
+			AnchorText: row.AnchorText,
+		}
+	}
+
+	return links, next, nil
+}
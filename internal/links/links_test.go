@@ -0,0 +1,86 @@
+package links
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
+)
+
+func TestOutlinksReindex(t *testing.T) {
+	ctx := context.Background()
+
+	d, err := db.Init(ctx, filepath.Join(t.TempDir(), "test.db"), func(int, string, string, int64) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.SQL.Close()
+
+	i := index.New(d, time.Hour)
+
+	source, _ := url.Parse("https://example.com/")
+	page := index.Page{URL: *source, Origin: *source}
+	for _, raw := range []string{"/c", "/a", "/b"} {
+		u, _ := source.Parse(raw)
+		page.Links = append(page.Links, index.Link{URL: *u, Text: raw})
+	}
+
+	if err = i.Add(ctx, page, []byte("links")); err != nil {
+		t.Fatal(err)
+	}
+
+	l := New(d)
+
+	var (
+		got   []string
+		token string
+	)
+	for {
+		resp, err := l.Outlinks(ctx, &pb.GetOutlinksRequest{Url: source.String(), PageSize: 1, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, link := range resp.GetLinks() {
+			got = append(got, link.GetTargetUrl())
+		}
+
+		if token = resp.GetNextPageToken(); token == "" {
+			break
+		}
+
+		// re-indexing the page replaces its links with new rows
+		if _, err = d.SQL.ExecContext(ctx, "UPDATE pages SET modified_at = ?", time.Now().UTC().Add(-2*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		slices.Reverse(page.Links)
+		if err = i.Add(ctx, page, []byte("links")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPageTokenURL(t *testing.T) {
+	token, err := cursor{URL: "https://example.com/a"}.encode("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = decodeCursor(token, "https://example.com/"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err = decodeCursor(token, "https://example.org/"); err == nil {
+		t.Error("page token was accepted for a different url")
+	}
+}
@@ -0,0 +1,233 @@
+package pagerank
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/registrar"
+)
+
+const (
+	DefaultDamping    = 0.85
+	DefaultIterations = 50
+	DefaultTolerance  = 1e-6
+	DefaultInterval   = 30 * time.Second
+)
+
+// Config contains the pagerank config
+type Config struct {
+	Damping    float64       // probability of following a link rather than jumping to a random page
+	Iterations uint32        // maximum number of iterations per computation
+	Tolerance  float64       // iteration stops once the total change in scores is below this
+	Interval   time.Duration // how long to wait after the link graph changes before recomputing
+}
+
+// Ranker computes the importance of every page from the link graph and stores
+// it with the page
+type Ranker struct {
+	db    *db.DB
+	cfg   Config
+	dirty chan struct{}
+}
+
+func New(d *db.DB, r registrar.Registrar, cfg Config) *Ranker {
+	pr := Ranker{
+		db:    d,
+		cfg:   cfg,
+		dirty: make(chan struct{}, 1),
+	}
+
+	r.Register("links", pr.onDBUpdate, db.SQLITE_INSERT)
+	r.Register("links", pr.onDBUpdate, db.SQLITE_DELETE)
+
+	return &pr
+}
+
+// onDBUpdate is called from within the write that changed the link graph, so
+// it only marks the scores as stale
+func (pr *Ranker) onDBUpdate(_ int64) {
+	select {
+	case pr.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// Run recomputes the scores whenever the link graph changes. changes are
+// batched by waiting for the configured interval, so a crawl adding many links
+// only causes a recomputation every interval. the scores are computed
+// immediately in case the graph changed while the process was stopped.
+func (pr *Ranker) Run(ctx context.Context) {
+	pr.onDBUpdate(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pr.dirty:
+		}
+
+		start := time.Now()
+		if err := pr.Update(ctx); err != nil {
+			slog.Warn("error updating pagerank", "err", err)
+		}
+
+		t := time.NewTimer(max(pr.cfg.Interval-time.Since(start), 0))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Update computes the score of every page and stores them. the computation
+// starts from the stored scores so it converges quickly when only a small part
+// of the graph has changed.
+func (pr *Ranker) Update(ctx context.Context) error {
+	pr.db.RLock()
+	pages, err := pr.db.ListPageRanks(ctx)
+	if err != nil {
+		pr.db.RUnlock()
+		return fmt.Errorf("error listing pages: %w", err)
+	}
+	links, err := pr.db.ListLinks(ctx)
+	pr.db.RUnlock()
+	if err != nil {
+		return fmt.Errorf("error listing links: %w", err)
+	}
+
+	if len(pages) == 0 {
+		return nil
+	}
+
+	idx := make(map[int64]int, len(pages))
+	rank := make([]float64, len(pages))
+	for i, p := range pages {
+		idx[p.ID] = i
+		rank[i] = p.Pagerank
+	}
+
+	g := graph{
+		out: make([]int, len(pages)),
+		in:  make([][]int, len(pages)),
+	}
+	for _, l := range links {
+		src, ok := idx[l.SourcePageID]
+		if !ok {
+			continue
+		}
+		dst, ok := idx[l.TargetPageID]
+		if !ok {
+			continue
+		}
+		g.out[src]++
+		g.in[dst] = append(g.in[dst], src)
+	}
+
+	start := time.Now()
+	n := g.rank(rank, pr.cfg)
+	slog.Info("pagerank computed", "pages", len(pages), "links", len(links), "iterations", n, "dur", time.Since(start))
+
+	// the most important page has a score of 1
+	var top float64
+	for _, r := range rank {
+		top = max(top, r)
+	}
+
+	pr.db.Lock()
+	defer pr.db.Unlock()
+
+	tx, err := pr.db.SQL.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction for pagerank update: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	queries := pr.db.WithTx(tx)
+
+	for i, p := range pages {
+		score := rank[i] / top
+		if math.Abs(score-p.Pagerank) < pr.cfg.Tolerance {
+			continue
+		}
+
+		err = queries.UpdatePageRank(ctx, db.UpdatePageRankParams{
+			Pagerank: score,
+			ID:       p.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating pagerank: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing pagerank transaction: %w", err)
+	}
+
+	return nil
+}
+
+type graph struct {
+	out []int   // number of outgoing links of each page
+	in  [][]int // pages linking to each page
+}
+
+// rank iterates the scores in place until they converge, or the maximum number
+// of iterations is reached, and returns the number of iterations. the initial
+// scores may be in any scale, if there are none every page starts out equal.
+func (g graph) rank(rank []float64, cfg Config) int {
+	n := float64(len(rank))
+
+	var sum float64
+	for _, r := range rank {
+		sum += r
+	}
+	if sum == 0 {
+		for i := range rank {
+			rank[i] = 1
+		}
+		sum = n
+	}
+	for i := range rank {
+		rank[i] /= sum
+	}
+
+	next := make([]float64, len(rank))
+
+	var iter int
+	for iter < int(cfg.Iterations) {
+		iter++
+
+		// pages without links spread their score over every page
+		var dangling float64
+		for i, r := range rank {
+			if g.out[i] == 0 {
+				dangling += r
+			}
+		}
+
+		base := (1-cfg.Damping)/n + cfg.Damping*dangling/n
+
+		var delta float64
+		for i := range next {
+			next[i] = base
+			for _, j := range g.in[i] {
+				next[i] += cfg.Damping * rank[j] / float64(g.out[j])
+			}
+			delta += math.Abs(next[i] - rank[i])
+		}
+
+		copy(rank, next)
+
+		if delta < cfg.Tolerance {
+			break
+		}
+	}
+
+	return iter
+}
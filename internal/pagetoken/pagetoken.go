@@ -0,0 +1,68 @@
+// Package pagetoken encodes the state needed to continue a listing on its next
+// page as an opaque token
+package pagetoken
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalid = errors.New("invalid page token")
+
+// token is the encoded form of a page token
+type token struct {
+	Scope string          `json:"s"`
+	State json.RawMessage `json:"c"`
+}
+
+// scopeHash identifies the scope a token was created for
+func scopeHash(scope string) string {
+	sum := sha256.Sum256([]byte(scope))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Encode returns a page token holding state. the token can only be decoded for
+// the same scope, e.g. the query or url that it continues the listing of.
+func Encode(scope string, state any) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+
+	data, err = json.Marshal(token{
+		Scope: scopeHash(scope),
+		State: data,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decode the state of a page token created by Encode for the same scope
+func Decode(s, scope string, state any) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	var t token
+	if err = json.Unmarshal(data, &t); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	if t.Scope != scopeHash(scope) {
+		return fmt.Errorf("%w: it was created for a different request", ErrInvalid)
+	}
+
+	if err = json.Unmarshal(t.State, state); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package query
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenPhrase
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenMinus
+	tokenNear
+	tokenLParen
+	tokenRParen
+	tokenField
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of query"
+	case tokenWord:
+		return "word"
+	case tokenPhrase:
+		return "phrase"
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	case tokenMinus:
+		return "-"
+	case tokenNear:
+		return "NEAR"
+	case tokenLParen:
+		return "("
+	case tokenRParen:
+		return ")"
+	case tokenField:
+		return "field"
+	default:
+		return "unknown"
+	}
+}
+
+type token struct {
+	kind     tokenKind
+	text     string
+	pos      int
+	distance uint32 // NEAR only
+	field    string // field only, text is its value
+}
+
+var nearRe = regexp.MustCompile(`^NEAR/(\d+)$`)
+
+// lex splits the query into tokens. operators must be upper case so that the
+// lower case words can still be searched for.
+func lex(query string) ([]token, error) {
+	var ret []token
+
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			ret = append(ret, token{kind: tokenLParen, text: "(", pos: i})
+			i += size
+		case r == ')':
+			ret = append(ret, token{kind: tokenRParen, text: ")", pos: i})
+			i += size
+		case r == '-' && (i == 0 || isBoundary(query, i)):
+			// a leading "-" negates what follows it
+			ret = append(ret, token{kind: tokenMinus, text: "-", pos: i})
+			i += size
+		case r == '"':
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				return nil, &Error{Pos: i, Msg: "unterminated quote"}
+			}
+			ret = append(ret, token{kind: tokenPhrase, text: query[i+1 : i+1+end], pos: i})
+			i += end + 2 //nolint:mnd
+		default:
+			start := i
+			for i < len(query) {
+				r, size := utf8.DecodeRuneInString(query[i:])
+				if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+					break
+				}
+				i += size
+			}
+
+			t := word(query[start:i], start)
+			if t.kind == tokenField && t.text == "" && i < len(query) && query[i] == '"' {
+				// the value of a field may be quoted
+				end := strings.IndexByte(query[i+1:], '"')
+				if end < 0 {
+					return nil, &Error{Pos: i, Msg: "unterminated quote"}
+				}
+				t.text = query[i+1 : i+1+end]
+				i += end + 2 //nolint:mnd
+			}
+			ret = append(ret, t)
+		}
+	}
+
+	return append(ret, token{kind: tokenEOF, pos: len(query)}), nil
+}
+
+// isBoundary reports whether the byte before i is whitespace or an open
+// parenthesis
+func isBoundary(query string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(query[:i])
+	return unicode.IsSpace(r) || r == '('
+}
+
+func word(text string, pos int) token {
+	switch text {
+	case "AND":
+		return token{kind: tokenAnd, text: text, pos: pos}
+	case "OR":
+		return token{kind: tokenOr, text: text, pos: pos}
+	case "NOT":
+		return token{kind: tokenNot, text: text, pos: pos}
+	}
+
+	if name, value, ok := strings.Cut(text, ":"); ok && slices.Contains(Fields, name) {
+		return token{kind: tokenField, text: value, pos: pos, field: name}
+	}
+
+	if m := nearRe.FindStringSubmatch(text); m != nil {
+		if distance, err := strconv.ParseUint(m[1], 10, 32); err == nil {
+			return token{kind: tokenNear, text: text, pos: pos, distance: uint32(distance)}
+		}
+	}
+
+	return token{kind: tokenWord, text: text, pos: pos}
+}
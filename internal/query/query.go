@@ -0,0 +1,356 @@
+// Package query parses search queries into an abstract syntax tree.
+//
+// The grammar, from lowest to highest precedence, is:
+//
+//	or    = and { "OR" and }
+//	and   = unary { [ "AND" ] unary }
+//	unary = ( "NOT" | "-" ) unary | near
+//	near  = primary { "NEAR/n" primary }
+//	primary = "(" or ")" | "\"phrase\"" | field | word
+//	field = ( "site" | "inurl" | "intitle" | "origin" | "depth" ) ":" value
+//
+// Adjacent clauses are implicitly joined with AND. Field values may be quoted.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Node is a node in the query syntax tree
+type Node interface {
+	fmt.Stringer
+	node()
+}
+
+// Term matches pages containing a word
+type Term struct {
+	Text string
+}
+
+// Phrase matches pages containing words in order
+type Phrase struct {
+	Text string
+}
+
+// Near matches pages where the terms, or phrases, are within Distance words of
+// each other
+type Near struct {
+	Left, Right Node
+	Distance    uint32
+}
+
+// Fields are the names of the field operators
+var Fields = []string{"site", "inurl", "intitle", "origin", "depth"}
+
+// Field matches pages whose url, title or origin matches Value. Name is one of
+// "site", "inurl", "intitle" or "origin".
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Depth matches pages whose depth compares to Depth with Op, one of "<", "<=",
+// "=", ">=" or ">"
+type Depth struct {
+	Op    string
+	Depth uint32
+}
+
+// Not matches pages that do not match Node
+type Not struct {
+	Node Node
+}
+
+// And matches pages that match all of Nodes
+type And struct {
+	Nodes []Node
+}
+
+// Or matches pages that match any of Nodes
+type Or struct {
+	Nodes []Node
+}
+
+func (Term) node()   {}
+func (Phrase) node() {}
+func (Near) node()   {}
+func (Field) node()  {}
+func (Depth) node()  {}
+func (Not) node()    {}
+func (And) node()    {}
+func (Or) node()     {}
+
+func (n Term) String() string   { return n.Text }
+func (n Phrase) String() string { return `"` + n.Text + `"` }
+func (n Near) String() string {
+	return fmt.Sprintf("(%s NEAR/%d %s)", n.Left, n.Distance, n.Right)
+}
+func (n Field) String() string {
+	if strings.ContainsFunc(n.Value, unicode.IsSpace) {
+		return n.Name + `:"` + n.Value + `"`
+	}
+	return n.Name + ":" + n.Value
+}
+func (n Depth) String() string { return fmt.Sprintf("depth:%s%d", n.Op, n.Depth) }
+func (n Not) String() string   { return "NOT " + n.Node.String() }
+func (n And) String() string   { return join(n.Nodes, " AND ") }
+func (n Or) String() string    { return join(n.Nodes, " OR ") }
+
+func join(nodes []Node, sep string) string {
+	s := make([]string, len(nodes))
+	for i, n := range nodes {
+		s[i] = n.String()
+	}
+	return "(" + strings.Join(s, sep) + ")"
+}
+
+// Error is returned when a query can not be parsed
+type Error struct {
+	Pos int // byte offset into the query
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+const (
+	// MaxLength is the longest query, in bytes, that is parsed
+	MaxLength = 2048
+
+	// MaxDepth is the deepest that groups and negations can be nested
+	MaxDepth = 32
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+// Parse the query into its syntax tree
+func Parse(query string) (Node, error) {
+	if len(query) > MaxLength {
+		return nil, &Error{Pos: MaxLength, Msg: fmt.Sprintf("query is longer than %d bytes", MaxLength)}
+	}
+
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 0, Msg: "empty query"}
+	}
+
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t.kind)}
+	}
+
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// enter a nested expression, the returned function leaves it
+func (p *parser) enter(t token) (func(), error) {
+	if p.depth++; p.depth > MaxDepth {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("query is nested more than %d deep", MaxDepth)}
+	}
+	return func() { p.depth-- }, nil
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) or() (Node, error) {
+	n, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{n}
+	for p.peek().kind == tokenOr {
+		p.next()
+		if n, err = p.and(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+
+	return Or{Nodes: nodes}, nil
+}
+
+// startsUnary reports whether the token can begin a unary expression, which
+// means that it is implicitly joined with AND to what came before it
+func startsUnary(k tokenKind) bool {
+	switch k {
+	case tokenWord, tokenPhrase, tokenField, tokenNot, tokenMinus, tokenLParen:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *parser) and() (Node, error) {
+	n, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{n}
+	for {
+		switch k := p.peek().kind; {
+		case k == tokenAnd:
+			p.next()
+		case startsUnary(k):
+		default:
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+			return And{Nodes: nodes}, nil
+		}
+
+		if n, err = p.unary(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+}
+
+func (p *parser) unary() (Node, error) {
+	switch p.peek().kind {
+	case tokenNot, tokenMinus:
+		leave, err := p.enter(p.next())
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Node: n}, nil
+	default:
+		return p.near()
+	}
+}
+
+func (p *parser) near() (Node, error) {
+	n, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenNear {
+		t := p.next()
+
+		right, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isLeaf(n) || !isLeaf(right) {
+			return nil, &Error{Pos: t.pos, Msg: "NEAR operands must be words or phrases"}
+		}
+
+		n = Near{Left: n, Right: right, Distance: t.distance}
+
+		if p.peek().kind == tokenNear {
+			return nil, &Error{Pos: p.peek().pos, Msg: "NEAR can not be chained"}
+		}
+	}
+
+	return n, nil
+}
+
+func isLeaf(n Node) bool {
+	switch n.(type) {
+	case Term, Phrase:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenWord:
+		return Term{Text: t.text}, nil
+	case tokenPhrase:
+		return Phrase{Text: t.text}, nil
+	case tokenField:
+		return field(t)
+	case tokenLParen:
+		leave, err := p.enter(t)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokenRParen {
+			return nil, &Error{Pos: c.pos, Msg: fmt.Sprintf("expected ) but found %s", c.kind)}
+		}
+		return n, nil
+	default:
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t.kind)}
+	}
+}
+
+var depthRe = regexp.MustCompile(`^(<=|>=|<|>|=)?(\d+)$`)
+
+func field(t token) (Node, error) {
+	if t.text == "" {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("%s: requires a value", t.field)}
+	}
+
+	if t.field != "depth" {
+		return Field{Name: t.field, Value: t.text}, nil
+	}
+
+	m := depthRe.FindStringSubmatch(t.text)
+	if m == nil {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("invalid depth: %q", t.text)}
+	}
+
+	depth, err := strconv.ParseUint(m[2], 10, 32)
+	if err != nil {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("invalid depth: %q", t.text)}
+	}
+
+	op := m[1]
+	if op == "" {
+		op = "="
+	}
+
+	return Depth{Op: op, Depth: uint32(depth)}, nil
+}
@@ -0,0 +1,115 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"news", "news"},
+		{"breaking news", "(breaking AND news)"},
+		{"breaking AND news", "(breaking AND news)"},
+		{"cats OR dogs", "(cats OR dogs)"},
+		{"a b OR c", "((a AND b) OR c)"},
+		{"a (b OR c)", "(a AND (b OR c))"},
+		{"NOT a", "NOT a"},
+		{"a -b", "(a AND NOT b)"},
+		{"a-b", "a-b"},
+		{"NOT NOT a", "NOT NOT a"},
+		{`"breaking news"`, `"breaking news"`},
+		{"breaking NEAR/3 news", "(breaking NEAR/3 news)"},
+		{`"a b" NEAR/2 c`, `("a b" NEAR/2 c)`},
+		{"and or not", "(and AND or AND not)"},
+		{"site:example.com", "site:example.com"},
+		{`intitle:"breaking news"`, `intitle:"breaking news"`},
+		{"depth:<=2", "depth:<=2"},
+		{"depth:3", "depth:=3"},
+		{"election site:cnn.com -depth:>1", "(election AND site:cnn.com AND NOT depth:>1)"},
+		{"other:value", "other:value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			n, err := Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := n.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{`"unterminated`, 0},
+		{"(a", 2},
+		{"a)", 1},
+		{"a OR", 4},
+		{"NOT", 3},
+		{"a NEAR/2 (b OR c)", 2},
+		{"a NEAR/2 b NEAR/2 c", 11},
+		{"site:", 0},
+		{"depth:abc", 0},
+		{"depth:99999999999", 0},
+		{strings.Repeat("(", MaxDepth+1) + "a" + strings.Repeat(")", MaxDepth+1), MaxDepth},
+		{strings.Repeat("NOT ", MaxDepth+1) + "a", MaxDepth * 4},
+		{strings.Repeat("a ", MaxLength), MaxLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query[:min(len(tt.query), 40)], func(t *testing.T) {
+			_, err := Parse(tt.query)
+
+			var qerr *Error
+			if !errors.As(err, &qerr) {
+				t.Fatalf("got error %v, want a query error", err)
+			}
+			if qerr.Pos != tt.pos {
+				t.Errorf("got position %d, want %d: %v", qerr.Pos, tt.pos, err)
+			}
+		})
+	}
+}
+
+func TestParseMaxDepth(t *testing.T) {
+	query := strings.Repeat("(", MaxDepth) + "a" + strings.Repeat(")", MaxDepth)
+	if _, err := Parse(query); err != nil {
+		t.Errorf("query nested %d deep: %v", MaxDepth, err)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, query := range []string{
+		"breaking news",
+		`a (b OR -c) NOT "d e"`,
+		"a NEAR/3 b",
+		`site:example.com/news intitle:"x y" depth:<=2`,
+		strings.Repeat("(", 100),
+	} {
+		f.Add(query)
+	}
+
+	f.Fuzz(func(t *testing.T, query string) {
+		n, err := Parse(query)
+		if err != nil {
+			var qerr *Error
+			if !errors.As(err, &qerr) {
+				t.Errorf("got error %v, want a query error", err)
+			}
+			return
+		}
+		_ = n.String()
+	})
+}
@@ -0,0 +1,367 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"time"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/scope"
+)
+
+// Crawl is an index request, every url queued for it shares its scope and
+// budgets
+type Crawl struct {
+	Origin   url.URL
+	MaxDepth uint32
+	Scope    scope.Scope
+
+	// budgets, zero is unlimited
+	MaxPages        uint32
+	MaxPagesPerHost uint32
+	Deadline        time.Time
+}
+
+var (
+	ErrDeadlinePassed = errors.New("crawl deadline has already passed")
+	ErrCrawlNotFound  = errors.New("crawl not found")
+)
+
+// CrawlStatus is a crawl and its progress
+type CrawlStatus struct {
+	Crawl
+	ID        int64
+	CreatedAt time.Time
+
+	Pages       int64 // urls that have been queued for the crawl
+	Queued      int64 // urls that are waiting to be fetched
+	DeadLetters int64 // urls that exhausted their retries
+	Truncated   bool  // a budget was exhausted
+}
+
+// crawlState is what is cached about a crawl to handle its urls
+type crawlState struct {
+	policy   *scope.Policy
+	deadline time.Time
+}
+
+// NewCrawl records the crawl and queues its origin. The id of the crawl is
+// returned.
+func (q *Queue) NewCrawl(ctx context.Context, c Crawl) (int64, error) {
+	c.Origin = *index.CleanURL(&c.Origin)
+
+	policy, err := c.Scope.Compile(c.Origin)
+	if err != nil {
+		return 0, err
+	}
+
+	if !c.Deadline.IsZero() && !time.Now().Before(c.Deadline) {
+		return 0, ErrDeadlinePassed
+	}
+
+	include, err := json.Marshal(patterns(c.Scope.Include))
+	if err != nil {
+		return 0, fmt.Errorf("error encoding include patterns: %w", err)
+	}
+
+	exclude, err := json.Marshal(patterns(c.Scope.Exclude))
+	if err != nil {
+		return 0, fmt.Errorf("error encoding exclude patterns: %w", err)
+	}
+
+	q.db.Lock()
+	crawl, err := q.db.InsertCrawl(ctx, db.InsertCrawlParams{
+		Origin:          c.Origin.String(),
+		MaxDepth:        int64(c.MaxDepth),
+		ScopeMode:       int64(c.Scope.Mode),
+		IncludePatterns: string(include),
+		ExcludePatterns: string(exclude),
+		MaxPages:        int64(c.MaxPages),
+		MaxPagesPerHost: int64(c.MaxPagesPerHost),
+		Deadline:        sql.NullTime{Time: c.Deadline.UTC(), Valid: !c.Deadline.IsZero()},
+	})
+	q.db.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("error inserting crawl: %w", err)
+	}
+
+	q.crawlsMu.Lock()
+	q.crawls[crawl.ID] = &crawlState{
+		policy:   policy,
+		deadline: c.Deadline,
+	}
+	q.crawlsMu.Unlock()
+
+	_, err = q.Add(ctx, Msg{
+		URL:      c.Origin,
+		Origin:   c.Origin,
+		MaxDepth: c.MaxDepth,
+		CrawlID:  crawl.ID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return crawl.ID, nil
+}
+
+// CrawlStatus returns the crawl with the given id and its progress
+func (q *Queue) CrawlStatus(ctx context.Context, crawlID int64) (CrawlStatus, error) {
+	q.db.RLock()
+	defer q.db.RUnlock()
+
+	crawl, err := q.db.GetCrawl(ctx, crawlID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return CrawlStatus{}, ErrCrawlNotFound
+	case err != nil:
+		return CrawlStatus{}, fmt.Errorf("error getting crawl: %w", err)
+	}
+
+	progress, err := q.db.GetCrawlProgress(ctx, crawlID)
+	if err != nil {
+		return CrawlStatus{}, fmt.Errorf("error getting crawl progress: %w", err)
+	}
+
+	c, err := decodeCrawl(crawl)
+	if err != nil {
+		return CrawlStatus{}, err
+	}
+
+	return CrawlStatus{
+		Crawl:       c,
+		ID:          crawl.ID,
+		CreatedAt:   crawl.CreatedAt,
+		Pages:       crawl.Pages,
+		Queued:      progress.Queued,
+		DeadLetters: progress.DeadLetters,
+		Truncated:   crawl.Truncated,
+	}, nil
+}
+
+// decodeCrawl converts a crawl from the way it is recorded
+func decodeCrawl(crawl db.Crawl) (Crawl, error) {
+	origin, err := url.Parse(crawl.Origin)
+	if err != nil {
+		return Crawl{}, fmt.Errorf("error parsing crawl origin: %w", err)
+	}
+
+	c := Crawl{
+		Origin:   *origin,
+		MaxDepth: uint32(crawl.MaxDepth),
+		Scope: scope.Scope{
+			Mode: scope.Mode(crawl.ScopeMode),
+		},
+		MaxPages:        uint32(crawl.MaxPages),
+		MaxPagesPerHost: uint32(crawl.MaxPagesPerHost),
+	}
+
+	if err = json.Unmarshal([]byte(crawl.IncludePatterns), &c.Scope.Include); err != nil {
+		return Crawl{}, fmt.Errorf("error decoding include patterns: %w", err)
+	}
+
+	if err = json.Unmarshal([]byte(crawl.ExcludePatterns), &c.Scope.Exclude); err != nil {
+		return Crawl{}, fmt.Errorf("error decoding exclude patterns: %w", err)
+	}
+
+	if crawl.Deadline.Valid {
+		c.Deadline = crawl.Deadline.Time
+	}
+
+	return c, nil
+}
+
+func patterns(p []string) []string {
+	if p == nil {
+		return []string{}
+	}
+	return p
+}
+
+// crawl returns the cached state of a crawl, loading it if necessary
+func (q *Queue) crawl(ctx context.Context, crawlID int64) (*crawlState, error) {
+	q.crawlsMu.Lock()
+	state, ok := q.crawls[crawlID]
+	q.crawlsMu.Unlock()
+	if ok {
+		return state, nil
+	}
+
+	q.db.RLock()
+	crawl, err := q.db.GetCrawl(ctx, crawlID)
+	q.db.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("error getting crawl: %w", err)
+	}
+
+	c, err := decodeCrawl(crawl)
+	if err != nil {
+		return nil, err
+	}
+
+	state = &crawlState{
+		deadline: c.Deadline,
+	}
+	if state.policy, err = c.Scope.Compile(c.Origin); err != nil {
+		return nil, err
+	}
+
+	q.crawlsMu.Lock()
+	q.crawls[crawlID] = state
+	q.crawlsMu.Unlock()
+
+	return state, nil
+}
+
+// evictCrawls drops crawls from the cache once they have no urls left to
+// crawl or their deadline has passed. They are loaded again if a url is
+// queued for them later.
+func (q *Queue) evictCrawls(ctx context.Context) {
+	q.crawlsMu.Lock()
+	states := maps.Clone(q.crawls)
+	q.crawlsMu.Unlock()
+
+	now := time.Now()
+	for id, state := range states {
+		if state.deadline.IsZero() || now.Before(state.deadline) {
+			q.db.RLock()
+			progress, err := q.db.GetCrawlProgress(ctx, id)
+			q.db.RUnlock()
+			if err != nil {
+				slog.Error("error getting crawl progress", "error", err, "crawl_id", id)
+				continue
+			}
+
+			if progress.Queued > 0 {
+				continue
+			}
+		}
+
+		q.crawlsMu.Lock()
+		delete(q.crawls, id)
+		q.crawlsMu.Unlock()
+	}
+}
+
+// Scope returns the scope policy of a crawl. Urls queued without a crawl
+// aren't limited, so their policy is nil, which allows everything.
+func (q *Queue) Scope(ctx context.Context, crawlID int64) (*scope.Policy, error) {
+	if crawlID == 0 {
+		return nil, nil
+	}
+
+	state, err := q.crawl(ctx, crawlID)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.policy, nil
+}
+
+// Expired reports whether the deadline of the crawl of msg has passed, in
+// which case the url shouldn't be crawled and the crawl is marked as truncated
+func (q *Queue) Expired(ctx context.Context, msg Msg) bool {
+	if msg.CrawlID == 0 {
+		return false
+	}
+
+	state, err := q.crawl(ctx, msg.CrawlID)
+	if err != nil {
+		slog.Error("error getting crawl", "error", err, "crawl_id", msg.CrawlID)
+		return false
+	}
+
+	if state.deadline.IsZero() || time.Now().Before(state.deadline) {
+		return false
+	}
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	if err = q.db.TruncateCrawl(ctx, msg.CrawlID); err != nil {
+		slog.Error("error truncating crawl", "error", err, "crawl_id", msg.CrawlID)
+	}
+
+	return true
+}
+
+// admit reports whether the crawl of msg has the budget for it to be queued.
+// If it doesn't, the crawl is marked as truncated, unless the url was already
+// queued anyway. Urls that were already counted against the page budgets are
+// only held to the deadline. It must be called with the write lock held.
+func (q *Queue) admit(ctx context.Context, queries *db.Queries, msg Msg, counted bool) (bool, error) {
+	crawl, err := queries.GetCrawl(ctx, msg.CrawlID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("error getting crawl: %w", err)
+	}
+
+	var reason string
+	switch {
+	case crawl.Deadline.Valid && !time.Now().Before(crawl.Deadline.Time):
+		reason = "deadline passed"
+	case counted:
+	case crawl.MaxPages > 0 && crawl.Pages >= crawl.MaxPages:
+		reason = "max pages reached"
+	case crawl.MaxPagesPerHost > 0:
+		pages, err := queries.GetCrawlHostPages(ctx, db.GetCrawlHostPagesParams{
+			CrawlID: msg.CrawlID,
+			Host:    msg.URL.Host,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("error getting crawl host pages: %w", err)
+		}
+		if pages >= crawl.MaxPagesPerHost {
+			reason = "max pages per host reached"
+		}
+	}
+
+	if reason == "" {
+		return true, nil
+	}
+
+	queued, err := queries.IsQueued(ctx, msg.URL.String())
+	if err != nil {
+		return false, fmt.Errorf("error checking if queued: %w", err)
+	}
+
+	if queued != 0 {
+		return false, nil
+	}
+
+	slog.Info("queue: crawl budget exhausted", "url", msg.URL.String(), "crawl_id", msg.CrawlID, "reason", reason)
+
+	if !crawl.Truncated {
+		if err = queries.TruncateCrawl(ctx, msg.CrawlID); err != nil {
+			return false, fmt.Errorf("error truncating crawl: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// count a url queued for a crawl against its budgets
+func (q *Queue) count(ctx context.Context, queries *db.Queries, msg Msg) error {
+	if err := queries.CountCrawlPage(ctx, msg.CrawlID); err != nil {
+		return fmt.Errorf("error counting crawl page: %w", err)
+	}
+
+	err := queries.CountCrawlHostPage(ctx, db.CountCrawlHostPageParams{
+		CrawlID: msg.CrawlID,
+		Host:    msg.URL.Host,
+	})
+	if err != nil {
+		return fmt.Errorf("error counting crawl host page: %w", err)
+	}
+
+	return nil
+}
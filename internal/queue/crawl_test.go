@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/registrar"
+	"github.com/joshuarubin/brightwave-google/internal/robots"
+)
+
+type nopRegistrar struct{}
+
+func (nopRegistrar) Register(string, registrar.Callback, int) {}
+
+// newQueue returns a queue with its own database, and the origin of a server
+// that allows everything to be crawled
+func newQueue(t *testing.T) (*Queue, url.URL) {
+	t.Helper()
+
+	d, err := db.Init(context.Background(), filepath.Join(t.TempDir(), "test.db"), func(int, string, string, int64) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SQL.Close() })
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	origin, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := New(d, index.New(d, time.Hour), robots.New(srv.Client(), "test", time.Hour), NewScheduler(0, 1), nopRegistrar{}, Config{
+		LeaseDur:    time.Minute,
+		MaxAttempts: 1,
+	})
+
+	return q, *origin
+}
+
+func (q *Queue) cached(id int64) bool {
+	q.crawlsMu.Lock()
+	defer q.crawlsMu.Unlock()
+
+	_, ok := q.crawls[id]
+	return ok
+}
+
+func TestEvictCrawls(t *testing.T) {
+	ctx := context.Background()
+	q, origin := newQueue(t)
+
+	id, err := q.NewCrawl(ctx, Crawl{Origin: origin, MaxDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q.evictCrawls(ctx)
+	if !q.cached(id) {
+		t.Fatal("crawl with queued urls was evicted")
+	}
+
+	msg, _, ok := q.next(ctx, 1)
+	if !ok {
+		t.Fatal("origin wasn't queued")
+	}
+	q.Ack(ctx, msg)
+
+	q.evictCrawls(ctx)
+	if q.cached(id) {
+		t.Fatal("finished crawl wasn't evicted")
+	}
+
+	// it is loaded again if it is needed
+	policy, err := q.Scope(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy == nil || !q.cached(id) {
+		t.Error("evicted crawl wasn't loaded again")
+	}
+}
+
+func TestRedriveBudget(t *testing.T) {
+	ctx := context.Background()
+	q, origin := newQueue(t)
+
+	id, err := q.NewCrawl(ctx, Crawl{Origin: origin, MaxDepth: 1, MaxPages: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, _, ok := q.next(ctx, 1)
+	if !ok {
+		t.Fatal("origin wasn't queued")
+	}
+	q.Retry(ctx, msg, errors.New("failed"), 0)
+
+	// the origin used the only page of the budget when it was first queued
+	n, err := q.Redrive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("redrove %d urls, want 1", n)
+	}
+
+	status, err := q.CrawlStatus(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Pages != 1 || status.Truncated {
+		t.Errorf("got %d pages, truncated %v, want the redriven url counted once", status.Pages, status.Truncated)
+	}
+}
@@ -0,0 +1,134 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+)
+
+// deadLetter moves a leased message from the queue to the dead-letter table
+func (q *Queue) deadLetter(ctx context.Context, msg Msg, cause error) error {
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	tx, err := q.db.SQL.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction for dead letter: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	queries := q.db.WithTx(tx)
+
+	err = queries.InsertDeadLetter(ctx, db.InsertDeadLetterParams{
+		URL:       msg.URL.String(),
+		Origin:    msg.Origin.String(),
+		Depth:     int64(msg.Depth),
+		MaxDepth:  int64(msg.MaxDepth),
+		Attempts:  int64(msg.Attempts) + 1,
+		LastError: cause.Error(),
+		CrawlID:   msg.CrawlID,
+	})
+	if err != nil {
+		return fmt.Errorf("error inserting dead letter: %w", err)
+	}
+
+	err = queries.AckLease(ctx, db.AckLeaseParams{
+		ID:       msg.ID,
+		WorkerID: sql.NullInt64{Int64: int64(msg.WorkerID), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("error removing dead letter from queue: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing dead letter transaction: %w", err)
+	}
+
+	slog.Warn("moved to dead-letter table", "url", msg.URL.String(), "attempts", msg.Attempts+1, "cause", cause)
+
+	return nil
+}
+
+// DeadLetters returns all of the urls that have exhausted their retries
+func (q *Queue) DeadLetters(ctx context.Context) ([]db.DeadLetter, error) {
+	q.db.RLock()
+	defer q.db.RUnlock()
+
+	return q.db.ListDeadLetters(ctx)
+}
+
+// Redrive returns dead letters to the queue with their attempts reset. If no
+// urls are given, all dead letters are redriven. Dead letters that Add doesn't
+// queue are kept. They were counted against the budgets of their crawl when
+// they were first queued, so they aren't counted again. The number of urls
+// that were requeued is returned.
+func (q *Queue) Redrive(ctx context.Context, urls ...string) (int, error) {
+	var items []db.DeadLetter
+	if len(urls) == 0 {
+		var err error
+		if items, err = q.DeadLetters(ctx); err != nil {
+			return 0, fmt.Errorf("error listing dead letters: %w", err)
+		}
+	}
+
+	for _, u := range urls {
+		q.db.RLock()
+		item, err := q.db.GetDeadLetter(ctx, u)
+		q.db.RUnlock()
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			slog.Warn("dead letter not found", "url", u)
+			continue
+		case err != nil:
+			return 0, fmt.Errorf("error getting dead letter: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	var n int
+	for _, item := range items {
+		u, err := url.Parse(item.URL)
+		if err != nil {
+			return n, fmt.Errorf("error parsing dead letter url: %w", err)
+		}
+
+		o, err := url.Parse(item.Origin)
+		if err != nil {
+			return n, fmt.Errorf("error parsing dead letter origin: %w", err)
+		}
+
+		queued, err := q.add(ctx, Msg{
+			URL:      *u,
+			Origin:   *o,
+			Depth:    uint32(item.Depth),
+			MaxDepth: uint32(item.MaxDepth),
+			CrawlID:  item.CrawlID,
+		}, true)
+		if err != nil {
+			return n, err
+		}
+
+		if !queued {
+			// kept so that it can be redriven once whatever stopped it has
+			// changed
+			slog.Info("dead letter not redriven", "url", item.URL)
+			continue
+		}
+
+		q.db.Lock()
+		err = q.db.DeleteDeadLetter(ctx, item.ID)
+		q.db.Unlock()
+		if err != nil {
+			return n, fmt.Errorf("error deleting dead letter: %w", err)
+		}
+
+		n++
+	}
+
+	return n, nil
+}
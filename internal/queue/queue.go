@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/joshuarubin/brightwave-google/internal/db"
 	"github.com/joshuarubin/brightwave-google/internal/index"
 	"github.com/joshuarubin/brightwave-google/internal/registrar"
+	"github.com/joshuarubin/brightwave-google/internal/robots"
 )
 
 type Msg struct {
@@ -19,39 +22,66 @@ type Msg struct {
 	Origin   url.URL
 	Depth    uint32
 	MaxDepth uint32
+
+	// the crawl the url was queued for, zero if it wasn't queued for one
+	CrawlID int64
+
+	// set on messages returned by Next, they identify the lease that must be
+	// acknowledged or released
+	ID       int64
+	WorkerID int
+	Attempts uint32
+}
+
+// Config contains the queue config
+type Config struct {
+	LeaseDur       time.Duration // how long a message may be held before being requeued
+	MaxAttempts    uint32        // attempts before a message is moved to the dead-letter table
+	RetryBaseDelay time.Duration // delay before the first retry, doubled on each attempt
+	RetryMaxDelay  time.Duration // maximum delay between retries
 }
 
 type Queue struct {
-	condMu sync.Mutex
-	cond   *sync.Cond
+	wakeMu sync.Mutex
+	wakeCh chan struct{}
 	index  *index.Index
+	robots *robots.Cache
+	sched  *Scheduler
 	db     *db.DB
+	cfg    Config
+
+	// crawls that urls have been queued for, by id
+	crawlsMu sync.Mutex
+	crawls   map[int64]*crawlState
 }
 
-func New(d *db.DB, i *index.Index, r registrar.Registrar) *Queue {
+func New(d *db.DB, i *index.Index, rc *robots.Cache, s *Scheduler, r registrar.Registrar, cfg Config) *Queue {
 	q := Queue{
-		index: i,
-		db:    d,
+		wakeCh: make(chan struct{}),
+		index:  i,
+		robots: rc,
+		sched:  s,
+		db:     d,
+		cfg:    cfg,
+
+		crawls: map[int64]*crawlState{},
 	}
-	q.cond = sync.NewCond(&q.condMu)
 
 	r.Register("queue", q.onDBInsert, db.SQLITE_INSERT)
 
 	return &q
 }
 
-func prepareMsg(item db.Queue) Msg {
+func prepareMsg(item db.Queue) (Msg, error) {
 	u, err := url.Parse(item.URL)
 	if err != nil {
-		slog.Error("error parsing url", "error", err, "url", item.URL)
-		return Msg{}
+		return Msg{}, fmt.Errorf("error parsing url: %w", err)
 	}
 	u = index.CleanURL(u)
 
 	o, err := url.Parse(item.Origin)
 	if err != nil {
-		slog.Error("error parsing origin url", "error", err, "url", item.URL)
-		return Msg{}
+		return Msg{}, fmt.Errorf("error parsing origin url: %w", err)
 	}
 	o = index.CleanURL(o)
 
@@ -60,70 +90,319 @@ func prepareMsg(item db.Queue) Msg {
 		Origin:   *o,
 		Depth:    uint32(item.Depth),
 		MaxDepth: uint32(item.MaxDepth),
-	}
+		CrawlID:  item.CrawlID,
+		ID:       item.ID,
+		WorkerID: int(item.WorkerID.Int64),
+		Attempts: uint32(item.Attempts),
+	}, nil
 }
 
-func (q *Queue) Next(ctx context.Context) <-chan Msg {
+const (
+	// MaxCandidateHosts is the number of hosts, ordered by their oldest queued
+	// url, that are considered each time the next url is chosen
+	MaxCandidateHosts = 1000
+
+	// MaxIdleWait is the longest Next will wait before checking the queue
+	// again
+	MaxIdleWait = 5 * time.Second
+)
+
+// Next returns a channel that will receive the next url to crawl, leased to
+// workerID. Urls are only returned for hosts that the scheduler allows to be
+// crawled, so throughput comes from breadth across hosts. Either Ack or
+// Release must be called with the message once it has been handled.
+func (q *Queue) Next(ctx context.Context, workerID int) <-chan Msg {
 	ch := make(chan Msg)
-	var emptiedDB bool
 
 	go func() {
-		if !emptiedDB {
-			// first process queue already in db
-			q.db.Lock()
-			item, err := q.db.Dequeue(ctx)
-			q.db.Unlock()
-			switch {
-			case errors.Is(err, sql.ErrNoRows):
-				emptiedDB = true
-			case err != nil:
-				slog.Error("error dequeuing", "error", err)
-				ch <- Msg{}
+		for {
+			// get the wake channel before checking the db so that an insert
+			// that happens during the check isn't missed
+			wake := q.wake()
+
+			msg, wait, ok := q.next(ctx, workerID)
+			if ok {
+				select {
+				case ch <- msg:
+				case <-ctx.Done():
+					q.Release(context.Background(), msg)
+				}
 				return
-			default:
-				ch <- prepareMsg(item)
+			}
+
+			// there wasn't anything that could be crawled yet, so wait until
+			// there is
+			t := time.NewTimer(wait)
+			select {
+			case <-wake:
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
 				return
 			}
+			t.Stop()
 		}
+	}()
 
-		// there wasn't anything queued, so wait until there is
+	return ch
+}
+
+// next attempts to lease a url from a host that is ready to be crawled. If
+// none are ready, it returns how long to wait before trying again.
+func (q *Queue) next(ctx context.Context, workerID int) (Msg, time.Duration, bool) {
+	now := time.Now().UTC()
+
+	q.db.RLock()
+	hosts, err := q.db.GetQueuedHosts(ctx, db.GetQueuedHostsParams{
+		NotBefore: now,
+		Limit:     MaxCandidateHosts,
+	})
+	q.db.RUnlock()
+	if err != nil {
+		slog.Error("error getting queued hosts", "error", err)
+		return Msg{}, MaxIdleWait, false
+	}
 
-		q.condMu.Lock()
-		q.cond.Wait()
-		q.condMu.Unlock()
+	wait := MaxIdleWait
+	for _, host := range hosts {
+		ok, hostWait := q.sched.Acquire(host)
+		if !ok {
+			if hostWait > 0 {
+				wait = min(wait, hostWait)
+			}
+			continue
+		}
 
 		q.db.Lock()
-		item, err := q.db.Dequeue(ctx)
+		item, err := q.db.LeaseHost(ctx, db.LeaseHostParams{
+			LeaseExpiresAt: sql.NullTime{Time: now.Add(q.cfg.LeaseDur), Valid: true},
+			WorkerID:       sql.NullInt64{Int64: int64(workerID), Valid: true},
+			Host:           host,
+			NotBefore:      now,
+		})
 		q.db.Unlock()
 
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// another crawler got to it first, no request was made so the
+			// host isn't delayed
+			q.sched.Cancel(host)
+			continue
+		case err != nil:
+			slog.Error("error dequeuing", "error", err, "host", host)
+			q.sched.Release(host)
+			continue
+		}
+
+		msg, err := prepareMsg(item)
 		if err != nil {
-			slog.Error("error dequeuing", "error", err)
-			ch <- Msg{}
+			// it can never be crawled, so drop it rather than leasing it again
+			// every time its lease expires
+			slog.Error("dropping queued url", "error", err, "url", item.URL)
+			q.drop(ctx, item)
+			q.sched.Cancel(host)
+			continue
+		}
+
+		if delay, err := q.robots.CrawlDelay(ctx, msg.URL); err == nil {
+			q.sched.SetCrawlDelay(host, delay)
+		}
+
+		return msg, 0, true
+	}
+
+	return Msg{}, wait, false
+}
+
+// Ack removes a message received from Next from the queue once it has been
+// successfully handled
+func (q *Queue) Ack(ctx context.Context, msg Msg) {
+	defer q.done(msg)
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	err := q.db.AckLease(ctx, db.AckLeaseParams{
+		ID:       msg.ID,
+		WorkerID: sql.NullInt64{Int64: int64(msg.WorkerID), Valid: true},
+	})
+	if err != nil {
+		slog.Error("error acknowledging lease", "error", err, "url", msg.URL.String())
+	}
+}
+
+// Retry returns a message received from Next to the queue after it failed to
+// be handled. It will not be dequeued again until the retry delay has elapsed,
+// which grows exponentially with each attempt, or retryAfter if it is longer.
+// Once the maximum number of attempts has been reached, the message is moved
+// to the dead-letter table instead.
+func (q *Queue) Retry(ctx context.Context, msg Msg, cause error, retryAfter time.Duration) {
+	defer q.done(msg)
+
+	attempts := msg.Attempts + 1
+	if attempts >= q.cfg.MaxAttempts {
+		if err := q.deadLetter(ctx, msg, cause); err != nil {
+			slog.Error("error moving to dead-letter table", "error", err, "url", msg.URL.String())
+		}
+		return
+	}
+
+	delay := max(q.backoff(attempts), retryAfter)
+	slog.Info("retrying", "url", msg.URL.String(), "attempts", attempts, "delay", delay, "cause", cause)
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	err := q.db.RetryLease(ctx, db.RetryLeaseParams{
+		NotBefore: time.Now().UTC().Add(delay),
+		LastError: cause.Error(),
+		ID:        msg.ID,
+		WorkerID:  sql.NullInt64{Int64: int64(msg.WorkerID), Valid: true},
+	})
+	if err != nil {
+		slog.Error("error retrying lease", "error", err, "url", msg.URL.String())
+		return
+	}
+
+	// wake up any waiting crawlers once the message can be dequeued again
+	time.AfterFunc(delay, q.notify)
+}
+
+// backoff returns the delay before the given attempt, with jitter so that
+// retries of urls that failed together don't all happen at once
+func (q *Queue) backoff(attempts uint32) time.Duration {
+	delay := q.cfg.RetryBaseDelay
+	for i := uint32(1); i < attempts && delay < q.cfg.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, q.cfg.RetryMaxDelay)
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1) //nolint:gosec,mnd
+}
+
+// Release returns a message received from Next to the queue so that it can be
+// tried again
+func (q *Queue) Release(ctx context.Context, msg Msg) {
+	defer q.done(msg)
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	err := q.db.ReleaseLease(ctx, db.ReleaseLeaseParams{
+		ID:       msg.ID,
+		WorkerID: sql.NullInt64{Int64: int64(msg.WorkerID), Valid: true},
+	})
+	if err != nil {
+		slog.Error("error releasing lease", "error", err, "url", msg.URL.String())
+	}
+}
+
+// drop removes a leased item from the queue without handling it
+func (q *Queue) drop(ctx context.Context, item db.Queue) {
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	err := q.db.AckLease(ctx, db.AckLeaseParams{
+		ID:       item.ID,
+		WorkerID: item.WorkerID,
+	})
+	if err != nil {
+		slog.Error("error dropping lease", "error", err, "url", item.URL)
+	}
+}
+
+// done allows the host of the message to be crawled again
+func (q *Queue) done(msg Msg) {
+	q.sched.Release(msg.URL.Host)
+	q.notify()
+}
+
+// Run periodically returns urls with expired leases to the queue, starting
+// immediately in order to recover any that were in flight when the process
+// last stopped. Crawls that are finished are dropped from the cache at the
+// same time.
+func (q *Queue) Run(ctx context.Context) {
+	t := time.NewTicker(max(q.cfg.LeaseDur/2, time.Second)) //nolint:mnd
+	defer t.Stop()
+
+	for {
+		q.requeueExpired(ctx)
+		q.evictCrawls(ctx)
+
+		select {
+		case <-ctx.Done():
 			return
+		case <-t.C:
 		}
+	}
+}
 
-		ch <- prepareMsg(item)
-	}()
+func (q *Queue) requeueExpired(ctx context.Context) {
+	q.db.Lock()
+	n, err := q.db.RequeueExpiredLeases(ctx, sql.NullTime{Time: time.Now().UTC(), Valid: true})
+	q.db.Unlock()
 
-	return ch
+	switch {
+	case err != nil:
+		slog.Error("error requeuing expired leases", "error", err)
+	case n > 0:
+		slog.Warn("requeued expired leases", "count", n)
+		q.notify()
+	}
+}
+
+// wake returns a channel that is closed the next time a url is enqueued or a
+// host is released
+func (q *Queue) wake() <-chan struct{} {
+	q.wakeMu.Lock()
+	defer q.wakeMu.Unlock()
+	return q.wakeCh
+}
+
+func (q *Queue) notify() {
+	q.wakeMu.Lock()
+	close(q.wakeCh)
+	q.wakeCh = make(chan struct{})
+	q.wakeMu.Unlock()
 }
 
 func (q *Queue) onDBInsert(_ int64) {
-	q.condMu.Lock()
-	q.cond.Signal()
-	q.condMu.Unlock()
+	q.notify()
 }
 
-func (q *Queue) Add(ctx context.Context, msg Msg) error {
+// Add queues the url in msg. It reports whether it was queued, urls that
+// robots.txt disallows, that don't need to be reindexed, that are already queued
+// or that are beyond the budgets of their crawl are not.
+func (q *Queue) Add(ctx context.Context, msg Msg) (bool, error) {
+	return q.add(ctx, msg, false)
+}
+
+// add queues the url in msg. If it was already counted against the budgets of
+// its crawl, when it was first queued, it isn't counted again.
+func (q *Queue) add(ctx context.Context, msg Msg, counted bool) (bool, error) {
 	msg.URL = *index.CleanURL(&msg.URL)
 	msg.Origin = *index.CleanURL(&msg.Origin)
 
+	allowed, err := q.robots.Allowed(ctx, msg.URL)
+	if err != nil {
+		return false, fmt.Errorf("error getting robots.txt: %w", err)
+	}
+
+	if !allowed {
+		slog.Info("queue: disallowed by robots.txt", "url", msg.URL.String())
+		return false, nil
+	}
+
 	q.db.RLock()
 
 	tx, err := q.db.SQL.Begin()
 	if err != nil {
 		q.db.RUnlock()
-		return fmt.Errorf("error starting transaction for queue add: %w", err)
+		return false, fmt.Errorf("error starting transaction for queue add: %w", err)
 	}
 	defer tx.Rollback() //nolint:errcheck
 
@@ -132,26 +411,49 @@ func (q *Queue) Add(ctx context.Context, msg Msg) error {
 	if !q.index.ShouldIndex(ctx, msg.URL, tx) {
 		q.db.RUnlock()
 		slog.Info("queue: not re-indexing", "url", msg.URL.String())
-		return nil
+		return false, nil
 	}
 
 	q.db.RUnlock()
 	q.db.Lock()
 	defer q.db.Unlock()
 
-	err = queries.Enqueue(ctx, db.EnqueueParams{
+	if msg.CrawlID != 0 {
+		ok, err := q.admit(ctx, queries, msg, counted)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			// commit the crawl being marked as truncated
+			if err = tx.Commit(); err != nil {
+				return false, fmt.Errorf("error committing enqueue transaction: %w", err)
+			}
+			return false, nil
+		}
+	}
+
+	n, err := queries.Enqueue(ctx, db.EnqueueParams{
 		URL:      msg.URL.String(),
+		Host:     msg.URL.Host,
 		Origin:   msg.Origin.String(),
 		Depth:    int64(msg.Depth),
 		MaxDepth: int64(msg.MaxDepth),
+		CrawlID:  msg.CrawlID,
 	})
 	if err != nil {
-		return fmt.Errorf("error enqueuing to db: %w", err)
+		return false, fmt.Errorf("error enqueuing to db: %w", err)
+	}
+
+	// urls that were already queued don't count against the budgets
+	if n > 0 && msg.CrawlID != 0 && !counted {
+		if err = q.count(ctx, queries, msg); err != nil {
+			return false, err
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("error committing enqueue transaction: %w", err)
+		return false, fmt.Errorf("error committing enqueue transaction: %w", err)
 	}
 
-	return nil
+	return n > 0, nil
 }
@@ -0,0 +1,66 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLease(t *testing.T) {
+	ctx := context.Background()
+	q, origin := newQueue(t)
+
+	if ok, err := q.Add(ctx, Msg{URL: origin, Origin: origin, MaxDepth: 1}); err != nil || !ok {
+		t.Fatalf("url wasn't queued: %v", err)
+	}
+
+	lease := func(workerID int) (Msg, bool) {
+		t.Helper()
+
+		msg, _, ok := q.next(ctx, workerID)
+		if ok {
+			// only the lease is under test, not the host delay
+			q.sched.Cancel(msg.URL.Host)
+		}
+		return msg, ok
+	}
+
+	msg, ok := lease(1)
+	if !ok {
+		t.Fatal("queued url wasn't leased")
+	}
+
+	if _, ok = lease(2); ok {
+		t.Fatal("leased url was leased again")
+	}
+
+	// the worker holding the lease stopped without acking it
+	_, err := q.db.SQL.ExecContext(ctx, "UPDATE queue SET lease_expires_at = ?", time.Now().UTC().Add(-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.requeueExpired(ctx)
+
+	expired, ok := lease(2)
+	if !ok || expired.URL != msg.URL {
+		t.Fatal("url with an expired lease wasn't leased again")
+	}
+
+	// the first worker's lease is gone, so its ack doesn't remove the url
+	q.Ack(ctx, msg)
+	if _, ok = lease(3); ok {
+		t.Fatal("url was leased while held by another worker")
+	}
+
+	q.Release(ctx, expired)
+	released, ok := lease(3)
+	if !ok {
+		t.Fatal("released url wasn't leased again")
+	}
+
+	q.Ack(ctx, released)
+	q.requeueExpired(ctx)
+	if _, ok = lease(4); ok {
+		t.Error("acked url is still queued")
+	}
+}
@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"sync"
+	"time"
+)
+
+// Scheduler enforces per-host politeness. It limits the number of concurrent
+// connections to each host and the minimum delay between requests to it.
+type Scheduler struct {
+	minDelay time.Duration
+	maxConns uint32
+
+	mu        sync.Mutex
+	hosts     map[string]*hostState
+	lastPrune time.Time
+}
+
+type hostState struct {
+	active     uint32
+	next       time.Time
+	prev       time.Time // next before the last Acquire, restored by Cancel
+	crawlDelay time.Duration
+}
+
+// PruneInterval is how often hosts that are idle and whose delay has elapsed
+// are forgotten, they are no different from hosts that were never crawled
+const PruneInterval = time.Minute
+
+func NewScheduler(minDelay time.Duration, maxConns uint32) *Scheduler {
+	return &Scheduler{
+		minDelay: minDelay,
+		maxConns: max(maxConns, 1),
+		hosts:    map[string]*hostState{},
+	}
+}
+
+func (s *Scheduler) host(host string) *hostState {
+	h, ok := s.hosts[host]
+	if !ok {
+		h = &hostState{}
+		s.hosts[host] = h
+	}
+	return h
+}
+
+func (s *Scheduler) delay(h *hostState) time.Duration {
+	return max(s.minDelay, h.crawlDelay)
+}
+
+// Acquire a connection slot for host. If the host is not ready, false is
+// returned along with how long until its delay has elapsed. A wait of 0 means
+// that all of its connections are in use.
+func (s *Scheduler) Acquire(host string) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.prune(now)
+
+	h := s.host(host)
+	if h.active >= s.maxConns {
+		return false, 0
+	}
+
+	if wait := h.next.Sub(now); wait > 0 {
+		return false, wait
+	}
+
+	h.active++
+	h.prev = h.next
+	h.next = now.Add(s.delay(h))
+
+	return true, 0
+}
+
+// prune forgets the hosts that have no connections and whose delay has
+// elapsed, so that the hosts of every url ever crawled aren't kept forever
+func (s *Scheduler) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < PruneInterval {
+		return
+	}
+	s.lastPrune = now
+
+	for name, h := range s.hosts {
+		if h.active == 0 && !h.next.After(now) {
+			delete(s.hosts, name)
+		}
+	}
+}
+
+// Release a connection slot previously acquired for host. The next request to
+// the host will not be allowed until its delay has elapsed from now.
+func (s *Scheduler) Release(host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.host(host)
+	if h.active > 0 {
+		h.active--
+	}
+	h.next = time.Now().Add(s.delay(h))
+}
+
+// Cancel a connection slot acquired for host that wasn't used to make a
+// request. The host is allowed again as soon as it would have been had the
+// slot never been acquired.
+func (s *Scheduler) Cancel(host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.host(host)
+	if h.active > 0 {
+		h.active--
+	}
+	h.next = h.prev
+}
+
+// SetCrawlDelay records the crawl-delay a host has requested in its
+// robots.txt. It is used instead of the minimum delay when it is longer.
+func (s *Scheduler) SetCrawlDelay(host string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.host(host).crawlDelay = d
+}
@@ -0,0 +1,65 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedulerDelay(t *testing.T) {
+	s := NewScheduler(time.Hour, 1)
+
+	if ok, _ := s.Acquire("a"); !ok {
+		t.Fatal("first acquire denied")
+	}
+
+	if ok, wait := s.Acquire("a"); ok || wait != 0 {
+		t.Errorf("acquired a busy host, got %v %v", ok, wait)
+	}
+
+	s.Release("a")
+
+	if ok, wait := s.Acquire("a"); ok || wait <= 0 {
+		t.Errorf("acquired before the delay elapsed, got %v %v", ok, wait)
+	}
+
+	if ok, _ := s.Acquire("b"); !ok {
+		t.Error("delay of one host applied to another")
+	}
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	s := NewScheduler(time.Hour, 1)
+
+	if ok, _ := s.Acquire("a"); !ok {
+		t.Fatal("first acquire denied")
+	}
+
+	s.Cancel("a")
+
+	// no request was made, so the host isn't delayed
+	if ok, wait := s.Acquire("a"); !ok {
+		t.Errorf("canceled slot delayed the host by %v", wait)
+	}
+}
+
+func TestSchedulerPrune(t *testing.T) {
+	s := NewScheduler(0, 1)
+
+	for _, host := range []string{"a", "b", "c"} {
+		if ok, _ := s.Acquire(host); !ok {
+			t.Fatalf("acquire %s denied", host)
+		}
+	}
+	s.Release("a")
+	s.Release("b")
+
+	s.lastPrune = time.Time{}
+	s.prune(time.Now().Add(time.Second))
+
+	if _, ok := s.hosts["a"]; ok {
+		t.Error("idle host wasn't pruned")
+	}
+	if _, ok := s.hosts["c"]; !ok {
+		t.Error("busy host was pruned")
+	}
+}
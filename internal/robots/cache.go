@@ -0,0 +1,156 @@
+package robots
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxSize is the maximum number of bytes of a robots.txt file that will
+	// be parsed, per RFC 9309
+	MaxSize = 500 * 1024
+
+	// ErrorTTL is how long a failure to fetch robots.txt is cached for. It is
+	// intentionally short as the failure causes the whole host to be
+	// disallowed.
+	ErrorTTL = 10 * time.Minute
+)
+
+// Cache fetches and caches robots.txt rules per host
+type Cache struct {
+	client *http.Client
+	agent  string
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	ready   chan struct{}
+	rules   *Rules
+	err     error
+	expires time.Time
+}
+
+// New returns a Cache that fetches robots.txt files with the given client and
+// evaluates them for agent. Successfully fetched files are cached for ttl.
+func New(client *http.Client, agent string, ttl time.Duration) *Cache {
+	return &Cache{
+		client:  client,
+		agent:   agent,
+		ttl:     ttl,
+		entries: map[string]*entry{},
+	}
+}
+
+// Allowed reports whether the url may be crawled. An error is only returned if
+// ctx is done before the rules are available.
+func (c *Cache) Allowed(ctx context.Context, u url.URL) (bool, error) {
+	rules, err := c.Get(ctx, u)
+	if err != nil {
+		return false, err
+	}
+	return rules.Allowed(c.agent, &u), nil
+}
+
+// CrawlDelay returns the crawl-delay the host of the url has requested
+func (c *Cache) CrawlDelay(ctx context.Context, u url.URL) (time.Duration, error) {
+	rules, err := c.Get(ctx, u)
+	if err != nil {
+		return 0, err
+	}
+	return rules.CrawlDelay(c.agent), nil
+}
+
+// Get returns the rules for the host of the given url, fetching them if they
+// are not already cached or have expired. Concurrent requests for the same
+// host share a single fetch. The fetch isn't tied to ctx, so a caller that gives
+// up doesn't fail it for the others, it is only bounded by the timeout of the
+// client. If ctx is done first, its error is returned.
+func (c *Cache) Get(ctx context.Context, u url.URL) (*Rules, error) {
+	key := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	switch {
+	case ok && e.expires.IsZero():
+		// a fetch is already in progress
+	case ok && time.Now().Before(e.expires):
+		c.mu.Unlock()
+		return e.rules, nil
+	default:
+		e = &entry{ready: make(chan struct{})}
+		c.entries[key] = e
+		go c.fill(context.WithoutCancel(ctx), key, e)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-e.ready:
+		if e.err != nil {
+			return nil, e.err
+		}
+		return e.rules, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fill the entry by fetching the rules for the host
+func (c *Cache) fill(ctx context.Context, key string, e *entry) {
+	rules, ttl, err := c.fetch(ctx, key)
+
+	c.mu.Lock()
+	if err != nil {
+		// the fetch was canceled, which says nothing about the host, so it
+		// isn't cached
+		e.err = err
+		delete(c.entries, key)
+	} else {
+		e.rules = rules
+		e.expires = time.Now().Add(ttl)
+	}
+	c.mu.Unlock()
+
+	close(e.ready)
+}
+
+// fetch the rules for the host. an error is only returned if ctx was canceled.
+func (c *Cache) fetch(ctx context.Context, key string) (*Rules, time.Duration, error) {
+	logger := slog.With("url", key+"/robots.txt")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key+"/robots.txt", nil)
+	if err != nil {
+		logger.Warn("error creating robots.txt request", "err", err)
+		return DisallowAll, ErrorTTL, nil
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		logger.Warn("error fetching robots.txt", "err", err)
+		return DisallowAll, ErrorTTL, nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices:
+		logger.Info("fetched robots.txt")
+		return Parse(io.LimitReader(resp.Body, MaxSize)), c.ttl, nil
+	case resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError:
+		// "unavailable", the crawler may access any resources on the server
+		return AllowAll, c.ttl, nil
+	default:
+		// "unreachable", the crawler must assume complete disallow
+		logger.Warn("robots.txt unreachable", "status", resp.StatusCode)
+		return DisallowAll, ErrorTTL, nil
+	}
+}
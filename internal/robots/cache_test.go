@@ -0,0 +1,105 @@
+package robots
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheStatus(t *testing.T) {
+	tests := []struct {
+		status  int
+		allowed bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, true},
+		{http.StatusForbidden, true},
+		{http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte("User-agent: *\nDisallow: /\n")) //nolint:errcheck
+			}))
+			defer srv.Close()
+
+			c := New(srv.Client(), "testbot", time.Hour)
+			u, _ := url.Parse(srv.URL + "/page")
+
+			allowed, err := c.Allowed(context.Background(), *u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != tt.allowed {
+				t.Errorf("got %v, want %v", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestCacheCanceledCaller(t *testing.T) {
+	var fetches atomic.Int32
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		<-release
+		w.Write([]byte("User-agent: *\nDisallow: /private\n")) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := New(srv.Client(), "testbot", time.Hour)
+	u, _ := url.Parse(srv.URL + "/page")
+
+	// the caller that starts the fetch gives up before it completes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.Allowed(ctx, *u); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+
+	// the fetch carried on without it, and its result is cached
+	allowed, err := c.Allowed(context.Background(), *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed {
+		t.Error("disallowed after a canceled caller")
+	}
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetched %d times, want 1", n)
+	}
+}
+
+func TestCacheClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := srv.Client()
+	client.Timeout = 10 * time.Millisecond
+
+	c := New(client, "testbot", time.Hour)
+	u, _ := url.Parse(srv.URL + "/page")
+
+	// the host didn't respond in time, so it is unreachable
+	allowed, err := c.Allowed(context.Background(), *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed {
+		t.Error("allowed when robots.txt timed out")
+	}
+}
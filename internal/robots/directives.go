@@ -0,0 +1,78 @@
+package robots
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Directives control whether a fetched page may be indexed and its links
+// followed. They are set with <meta name="robots"> tags and X-Robots-Tag
+// headers.
+type Directives struct {
+	NoIndex  bool
+	NoFollow bool
+}
+
+// Merge returns the directives that apply when both d and o are given, the
+// most restrictive one wins
+func (d Directives) Merge(o Directives) Directives {
+	return Directives{
+		NoIndex:  d.NoIndex || o.NoIndex,
+		NoFollow: d.NoFollow || o.NoFollow,
+	}
+}
+
+// ParseDirectives parses a comma separated list of directives, as found in the
+// content of a robots meta tag. Unknown directives are ignored.
+func ParseDirectives(s string) Directives {
+	var d Directives
+	for _, v := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "noindex":
+			d.NoIndex = true
+		case "nofollow":
+			d.NoFollow = true
+		case "none":
+			d.NoIndex = true
+			d.NoFollow = true
+		}
+	}
+	return d
+}
+
+// MetaApplies reports whether a meta tag with the given name sets the
+// directives for the agent, i.e. it is named robots or after the agent
+func MetaApplies(agent, name string) bool {
+	name = strings.TrimSpace(name)
+	return strings.EqualFold(name, "robots") || strings.EqualFold(name, agent)
+}
+
+// directives that take a value after a colon, so that a value starting with one
+// of them isn't mistaken for one prefixed by a user agent
+var valueDirectives = []string{
+	"unavailable_after",
+	"max-snippet",
+	"max-image-preview",
+	"max-video-preview",
+}
+
+// HeaderDirectives parses the X-Robots-Tag headers that apply to the agent. A
+// header may be prefixed by the user agent it applies to, e.g.
+// "X-Robots-Tag: otherbot: noindex", otherwise it applies to every agent.
+func HeaderDirectives(agent string, h http.Header) Directives {
+	var d Directives
+	for _, v := range h.Values("X-Robots-Tag") {
+		if prefix, rest, ok := strings.Cut(v, ":"); ok {
+			prefix = strings.ToLower(strings.TrimSpace(prefix))
+			if !strings.Contains(prefix, ",") && !slices.Contains(valueDirectives, prefix) {
+				if prefix != strings.ToLower(agent) {
+					continue
+				}
+				v = rest
+			}
+		}
+		d = d.Merge(ParseDirectives(v))
+	}
+	return d
+}
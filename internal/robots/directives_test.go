@@ -0,0 +1,37 @@
+package robots
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHeaderDirectives(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   Directives
+	}{
+		{"none", nil, Directives{}},
+		{"every agent", []string{"noindex"}, Directives{NoIndex: true}},
+		{"list", []string{"NoIndex, NoFollow"}, Directives{NoIndex: true, NoFollow: true}},
+		{"shorthand", []string{"none"}, Directives{NoIndex: true, NoFollow: true}},
+		{"this agent", []string{"TestBot: nofollow"}, Directives{NoFollow: true}},
+		{"other agent", []string{"otherbot: noindex"}, Directives{}},
+		{"merged", []string{"noindex", "testbot: nofollow"}, Directives{NoIndex: true, NoFollow: true}},
+		{"value directive", []string{"unavailable_after: 2030-01-01, noindex"}, Directives{NoIndex: true}},
+		{"unknown", []string{"noarchive, nosnippet"}, Directives{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for _, v := range tt.values {
+				h.Add("X-Robots-Tag", v)
+			}
+
+			if got := HeaderDirectives("testbot", h); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
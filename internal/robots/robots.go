@@ -0,0 +1,202 @@
+package robots
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rules is a parsed robots.txt file as described in RFC 9309
+type Rules struct {
+	groups []*group
+}
+
+type group struct {
+	agents     []string
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+// AllowAll is returned when a robots.txt file does not exist
+var AllowAll = &Rules{}
+
+// DisallowAll is returned when a robots.txt file could not be fetched due to a
+// server or network error
+var DisallowAll = &Rules{
+	groups: []*group{{
+		agents: []string{"*"},
+		rules:  []rule{{pattern: "/"}},
+	}},
+}
+
+// Parse a robots.txt file. Unknown and malformed lines are ignored.
+func Parse(r io.Reader) *Rules {
+	var (
+		ret     Rules
+		cur     *group
+		inRules bool
+	)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+
+		switch key {
+		case "user-agent":
+			if cur == nil || inRules {
+				cur = &group{}
+				ret.groups = append(ret.groups, cur)
+				inRules = false
+			}
+			agent, _, _ := strings.Cut(val, "/")
+			cur.agents = append(cur.agents, strings.ToLower(strings.TrimSpace(agent)))
+		case "allow", "disallow":
+			if cur == nil {
+				continue
+			}
+			inRules = true
+			if val == "" {
+				// an empty disallow means nothing is disallowed
+				continue
+			}
+			cur.rules = append(cur.rules, rule{
+				allow:   key == "allow",
+				pattern: val,
+			})
+		case "crawl-delay":
+			if cur == nil {
+				continue
+			}
+			inRules = true
+			if d, err := strconv.ParseFloat(val, 64); err == nil && d > 0 {
+				cur.crawlDelay = time.Duration(d * float64(time.Second))
+			}
+		}
+	}
+
+	return &ret
+}
+
+// groupsFor returns all of the groups that apply to the given agent. groups that
+// explicitly name the agent take precedence over the "*" groups.
+func (r *Rules) groupsFor(agent string) []*group {
+	agent = strings.ToLower(agent)
+
+	var named, global []*group
+	for _, g := range r.groups {
+		switch {
+		case slices.Contains(g.agents, agent):
+			named = append(named, g)
+		case slices.Contains(g.agents, "*"):
+			global = append(global, g)
+		}
+	}
+
+	if len(named) > 0 {
+		return named
+	}
+
+	return global
+}
+
+// Allowed reports whether the agent may fetch the given url. The longest
+// matching rule wins and allow rules win ties.
+func (r *Rules) Allowed(agent string, u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	if path == "/robots.txt" {
+		return true
+	}
+
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed := true
+	longest := -1
+	for _, g := range r.groupsFor(agent) {
+		for _, rl := range g.rules {
+			if !match(rl.pattern, path) {
+				continue
+			}
+			n := len(rl.pattern)
+			if n > longest || (n == longest && rl.allow) {
+				longest = n
+				allowed = rl.allow
+			}
+		}
+	}
+
+	return allowed
+}
+
+// CrawlDelay returns the crawl-delay requested for the agent, or 0 if none was
+// given
+func (r *Rules) CrawlDelay(agent string) time.Duration {
+	var ret time.Duration
+	for _, g := range r.groupsFor(agent) {
+		ret = max(ret, g.crawlDelay)
+	}
+	return ret
+}
+
+// match reports whether path matches the robots.txt pattern. "*" matches any
+// sequence of characters and a trailing "$" anchors the pattern to the end of
+// the path. otherwise patterns are prefix matches.
+func match(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+
+	parts := strings.Split(pattern, "*")
+
+	// the first part must be a prefix
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	path = path[len(parts[0]):]
+	parts = parts[1:]
+
+	if len(parts) == 0 {
+		return !anchored || path == ""
+	}
+
+	// the middle parts may be found anywhere, in order
+	last := parts[len(parts)-1]
+	for _, p := range parts[:len(parts)-1] {
+		i := strings.Index(path, p)
+		if i < 0 {
+			return false
+		}
+		path = path[i+len(p):]
+	}
+
+	if anchored {
+		return strings.HasSuffix(path, last)
+	}
+
+	return strings.Contains(path, last)
+}
@@ -0,0 +1,139 @@
+package robots
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `
+# comments are ignored
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow: /search?
+Crawl-delay: 2
+
+User-agent: TestBot/1.0
+User-agent: otherbot
+Disallow: /bots/
+Allow: /bots/welcome
+Crawl-delay: 0.5
+
+User-agent: blocked
+Disallow: /
+`
+
+func TestAllowed(t *testing.T) {
+	rules := Parse(strings.NewReader(robotsTxt))
+
+	tests := []struct {
+		agent string
+		path  string
+		want  bool
+	}{
+		{"anybot", "/", true},
+		{"anybot", "/private/", false},
+		{"anybot", "/private/page", false},
+		{"anybot", "/private/public", true},
+		{"anybot", "/private/public/more", true},
+		{"anybot", "/doc.pdf", false},
+		{"anybot", "/doc.pdf?x=1", true},
+		{"anybot", "/doc.pdfs", true},
+		{"anybot", "/search", true},
+		{"anybot", "/search?q=x", false},
+		{"testbot", "/private/", true},
+		{"TestBot", "/bots/", false},
+		{"otherbot", "/bots/welcome", true},
+		{"blocked", "/", false},
+		{"blocked", "/anything", false},
+		{"blocked", "/robots.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent+" "+tt.path, func(t *testing.T) {
+			u, err := url.Parse("https://example.com" + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rules.Allowed(tt.agent, u); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrawlDelay(t *testing.T) {
+	rules := Parse(strings.NewReader(robotsTxt))
+
+	tests := []struct {
+		agent string
+		want  time.Duration
+	}{
+		{"anybot", 2 * time.Second},
+		{"testbot", 500 * time.Millisecond},
+		{"blocked", 0},
+	}
+
+	for _, tt := range tests {
+		if got := rules.CrawlDelay(tt.agent); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.agent, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/", "/", true},
+		{"/a", "/abc", true},
+		{"/a$", "/abc", false},
+		{"/a$", "/a", true},
+		{"/*/b", "/a/b", true},
+		{"/*/b", "/a/c", false},
+		{"/a*c*e", "/abcde", true},
+		{"/a*c*e", "/abcd", false},
+		{"/a*e$", "/abcde", true},
+		{"/a*e$", "/abcdef", false},
+		{"*", "/anything", true},
+		{"/x", "/", false},
+	}
+
+	for _, tt := range tests {
+		if got := match(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAllowAllDisallowAll(t *testing.T) {
+	u, _ := url.Parse("https://example.com/page")
+
+	if !AllowAll.Allowed("anybot", u) {
+		t.Error("AllowAll disallowed")
+	}
+	if DisallowAll.Allowed("anybot", u) {
+		t.Error("DisallowAll allowed")
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(robotsTxt, "/private/page")
+	f.Add("User-agent: *\nDisallow: /*a*b*$\n", "/aab")
+
+	f.Fuzz(func(t *testing.T, txt, path string) {
+		rules := Parse(strings.NewReader(txt))
+
+		u, err := url.Parse("https://example.com/" + strings.TrimPrefix(path, "/"))
+		if err != nil {
+			return
+		}
+		rules.Allowed("anybot", u)
+		rules.CrawlDelay("anybot")
+	})
+}
@@ -0,0 +1,200 @@
+package scope
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Mode restricts which hosts a crawl may follow links to
+type Mode int
+
+const (
+	// ModeAny allows links to any host
+	ModeAny Mode = iota
+
+	// ModeHost only allows links to the host of the origin
+	ModeHost
+
+	// ModeDomain only allows links to hosts under the registrable domain of the
+	// origin, e.g. www.example.co.uk and news.example.co.uk
+	ModeDomain
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeAny:
+		return "any"
+	case ModeHost:
+		return "host"
+	case ModeDomain:
+		return "domain"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode parses the name of a mode as returned by Mode.String
+func ParseMode(s string) (Mode, error) {
+	for _, m := range []Mode{ModeAny, ModeHost, ModeDomain} {
+		if strings.EqualFold(s, m.String()) {
+			return m, nil
+		}
+	}
+	return ModeAny, fmt.Errorf("%w: unknown mode %q", ErrInvalidScope, s)
+}
+
+var ErrInvalidScope = errors.New("invalid scope")
+
+// RegexPrefix marks a pattern as a regular expression rather than a glob
+const RegexPrefix = "re:"
+
+// Scope is the policy that decides which links a crawl follows. Patterns are
+// matched against the whole url. They are globs, where * matches any run of
+// characters and ? a single one, unless they start with RegexPrefix, in which
+// case the rest is an unanchored regular expression.
+type Scope struct {
+	Mode Mode
+
+	// if there are any, a url must match one of them
+	Include []string
+
+	// a url that matches any of them is never followed
+	Exclude []string
+}
+
+// Policy is a scope compiled for the origin of a crawl
+type Policy struct {
+	mode    Mode
+	host    string
+	domain  string
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// Compile the scope for a crawl starting at origin
+func (s Scope) Compile(origin url.URL) (*Policy, error) {
+	p := Policy{
+		mode: s.Mode,
+		host: strings.ToLower(origin.Hostname()),
+	}
+	p.domain = Domain(p.host)
+
+	switch s.Mode {
+	case ModeAny, ModeHost, ModeDomain:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %d", ErrInvalidScope, s.Mode)
+	}
+
+	var err error
+	if p.include, err = compile(s.Include); err != nil {
+		return nil, err
+	}
+
+	if p.exclude, err = compile(s.Exclude); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	ret := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr, ok := strings.CutPrefix(pattern, RegexPrefix)
+		if !ok {
+			expr = glob(pattern)
+		}
+
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: pattern %q: %w", ErrInvalidScope, pattern, err)
+		}
+		ret = append(ret, re)
+	}
+	return ret, nil
+}
+
+// glob returns the anchored regular expression equivalent to the glob
+func glob(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// Domain returns the registrable domain of the host, the host itself is
+// returned for ip addresses and hosts that don't have one
+func Domain(host string) string {
+	host = strings.ToLower(host)
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	d, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+
+	return d
+}
+
+// SameDomain reports whether the hosts of the urls share a registrable domain
+func SameDomain(a, b url.URL) bool {
+	return Domain(a.Hostname()) == Domain(b.Hostname())
+}
+
+// Allows reports whether links to u may be followed, a nil policy allows
+// everything
+func (p *Policy) Allows(u url.URL) bool {
+	if p == nil {
+		return true
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	switch p.mode {
+	case ModeHost:
+		if host != p.host {
+			return false
+		}
+	case ModeDomain:
+		if Domain(host) != p.domain {
+			return false
+		}
+	}
+
+	s := u.String()
+
+	for _, re := range p.exclude {
+		if re.MatchString(s) {
+			return false
+		}
+	}
+
+	if len(p.include) == 0 {
+		return true
+	}
+
+	for _, re := range p.include {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
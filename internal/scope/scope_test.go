@@ -0,0 +1,139 @@
+package scope
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope Scope
+		url   string
+		want  bool
+	}{
+		{"any", Scope{}, "https://other.com/", true},
+		{"host", Scope{Mode: ModeHost}, "https://www.example.co.uk/a", true},
+		{"host port", Scope{Mode: ModeHost}, "https://www.example.co.uk:8080/a", true},
+		{"host case", Scope{Mode: ModeHost}, "https://WWW.Example.co.uk/a", true},
+		{"other host", Scope{Mode: ModeHost}, "https://news.example.co.uk/a", false},
+		{"domain", Scope{Mode: ModeDomain}, "https://news.example.co.uk/a", true},
+		{"bare domain", Scope{Mode: ModeDomain}, "https://example.co.uk/a", true},
+		{"other domain", Scope{Mode: ModeDomain}, "https://other.co.uk/a", false},
+		{"public suffix", Scope{Mode: ModeDomain}, "https://co.uk/a", false},
+		{"include", Scope{Include: []string{"https://*/news/*"}}, "https://x.com/news/a", true},
+		{"include anchored", Scope{Include: []string{"https://*/news/*"}}, "https://x.com/a/news/", true},
+		{"not included", Scope{Include: []string{"https://*/news/*"}}, "https://x.com/sport/a", false},
+		{"glob literal", Scope{Include: []string{"https://x.com/a.html"}}, "https://x.com/aXhtml", false},
+		{"glob single", Scope{Include: []string{"https://x.com/?.html"}}, "https://x.com/a.html", true},
+		{"exclude", Scope{Exclude: []string{"*/videos/*"}}, "https://x.com/videos/1", false},
+		{"exclude wins", Scope{Include: []string{"*"}, Exclude: []string{"*.pdf"}}, "https://x.com/a.pdf", false},
+		{"regex", Scope{Include: []string{"re:/20[0-9]{2}/"}}, "https://x.com/2024/a", true},
+		{"regex unanchored", Scope{Exclude: []string{"re:\\?"}}, "https://x.com/a?b=1", false},
+	}
+
+	origin, _ := url.Parse("https://www.example.co.uk/")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.scope.Compile(*origin)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := p.Allows(*u); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilPolicy(t *testing.T) {
+	var p *Policy
+	u, _ := url.Parse("https://example.com/")
+	if !p.Allows(*u) {
+		t.Error("nil policy disallowed")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	origin, _ := url.Parse("https://example.com/")
+
+	for _, s := range []Scope{
+		{Mode: Mode(99)},
+		{Include: []string{"re:("}},
+		{Exclude: []string{"re:[a-"}},
+	} {
+		if _, err := s.Compile(*origin); !errors.Is(err, ErrInvalidScope) {
+			t.Errorf("%+v: got error %v, want %v", s, err, ErrInvalidScope)
+		}
+	}
+}
+
+func TestDomain(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"www.example.com", "example.com"},
+		{"News.Example.co.uk", "example.co.uk"},
+		{"example.github.io", "example.github.io"},
+		{"127.0.0.1", "127.0.0.1"},
+		{"::1", "::1"},
+		{"localhost", "localhost"},
+	}
+
+	for _, tt := range tests {
+		if got := Domain(tt.host); got != tt.want {
+			t.Errorf("Domain(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func FuzzGlob(f *testing.F) {
+	f.Add("https://*/news/?.html", "https://x.com/news/a.html")
+	f.Add("*.[pdf](", "a.[pdf](")
+
+	origin, _ := url.Parse("https://example.com/")
+
+	f.Fuzz(func(t *testing.T, pattern, s string) {
+		if strings.HasPrefix(pattern, RegexPrefix) {
+			return
+		}
+
+		// globs never fail to compile, whatever characters they contain
+		p, err := Scope{Include: []string{pattern}}.Compile(*origin)
+		if err != nil {
+			t.Fatalf("pattern %q: %v", pattern, err)
+		}
+
+		// every glob matches itself, as * and ? match themselves too
+		u, err := url.Parse(s)
+		if err != nil || u.String() != pattern {
+			return
+		}
+		if !p.Allows(*u) {
+			t.Errorf("pattern %q doesn't match itself", pattern)
+		}
+	})
+}
+
+func TestParseMode(t *testing.T) {
+	for _, m := range []Mode{ModeAny, ModeHost, ModeDomain} {
+		got, err := ParseMode(strings.ToUpper(m.String()))
+		if err != nil || got != m {
+			t.Errorf("ParseMode(%q) = %v, %v, want %v", m.String(), got, err, m)
+		}
+	}
+
+	if _, err := ParseMode("everywhere"); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("got error %v, want %v", err, ErrInvalidScope)
+	}
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"math"
+
+	"github.com/joshuarubin/brightwave-google/internal/index"
+)
+
+const (
+	DefaultK1 = 1.2
+	DefaultB  = 0.75
+
+	DefaultTitleWeight       = 3
+	DefaultDescriptionWeight = 1.5
+	DefaultHeadingsWeight    = 2
+	DefaultAnchorWeight      = 2
+
+	DefaultPageRankWeight = 1
+)
+
+// idf returns the inverse document frequency of a term that appears in df of
+// the n indexed pages
+func idf(n, df int64) float64 {
+	return math.Log(1 + (float64(n)-float64(df)+0.5)/(float64(df)+0.5)) //nolint:mnd
+}
+
+// bm25 returns the score contribution of a term that appears tf times in a page
+// of the given length
+func (s *Search) bm25(idf float64, tf, length int64, avgLength float64) float64 {
+	norm := 1.0
+	if avgLength > 0 {
+		norm = 1 - s.cfg.B + s.cfg.B*float64(length)/avgLength
+	}
+
+	return idf * float64(tf) * (s.cfg.K1 + 1) / (float64(tf) + s.cfg.K1*norm)
+}
+
+// weight returns how much a match in the field counts relative to one in the
+// body
+func (s *Search) weight(field string) float64 {
+	switch field {
+	case index.FieldTitle:
+		return s.cfg.TitleWeight
+	case index.FieldDescription:
+		return s.cfg.DescriptionWeight
+	case index.FieldHeadings:
+		return s.cfg.HeadingsWeight
+	case index.FieldAnchor:
+		return s.cfg.AnchorWeight
+	default:
+		return 1
+	}
+}
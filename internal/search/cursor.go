@@ -0,0 +1,47 @@
+package search
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/joshuarubin/brightwave-google/internal/pagetoken"
+)
+
+// cursor is the state needed to continue a search on the next page. it
+// identifies the ranking computed for the first page, which later pages are
+// read from, and records the key of the last result returned so that the next
+// page starts right after it, even if the ranking has expired and had to be
+// computed again.
+type cursor struct {
+	Ranking string  `json:"r"`
+	Score   float64 `json:"s"`
+	PageID  int64   `json:"p"`
+}
+
+// after reports whether p ranks after the last result of the previous page
+func (c cursor) after(p *RankedPage) bool {
+	if p.Score != c.Score {
+		return p.Score < c.Score
+	}
+	return p.PageID > c.PageID
+}
+
+// encode the cursor as a page token for the query
+func (c cursor) encode(query string) (string, error) {
+	return pagetoken.Encode(query, c)
+}
+
+// decodeCursor decodes a page token, which must have been created for the same
+// query
+func decodeCursor(token, query string) (cursor, error) {
+	var c cursor
+	if err := pagetoken.Decode(token, query, &c); err != nil {
+		return cursor{}, err
+	}
+
+	if c.PageID <= 0 || math.IsNaN(c.Score) {
+		return cursor{}, fmt.Errorf("%w: invalid position", pagetoken.ErrInvalid)
+	}
+
+	return c, nil
+}
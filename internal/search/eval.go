@@ -0,0 +1,466 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/query"
+	"github.com/joshuarubin/brightwave-google/internal/text"
+)
+
+// result is the set of pages matched by a query node and their bm25 scores
+type result struct {
+	// all is set when the node consisted only of words ignored by the
+	// tokenizer (e.g. stopwords). it places no constraint on the pages that
+	// match.
+	all    bool
+	scores map[int64]float64
+}
+
+// evaluator evaluates a query syntax tree against the postings of its terms
+type evaluator struct {
+	s *Search
+	c *corpus
+
+	leaves   map[string][]seqTerm // the tokenized text of each term and phrase
+	terms    map[string][]string  // every term in the query, by field
+	positive []string             // terms, not restricted to a field, that are not negated
+	scored   bool                 // the query has terms that aren't negated, so matches have a relevance score
+
+	pages []db.ListPagesRow // every indexed page, loaded when first needed
+}
+
+// compile tokenizes the text of every term and phrase in the query
+func (e *evaluator) compile(n query.Node, negated bool) error {
+	var (
+		data   string
+		fields = index.Fields
+	)
+
+	switch n := n.(type) {
+	case query.Term:
+		data = n.Text
+	case query.Phrase:
+		data = n.Text
+	case query.Field:
+		if n.Name != "intitle" {
+			return nil
+		}
+		data, fields = n.Value, []string{index.FieldTitle}
+	case query.Depth:
+		return nil
+	case query.Near:
+		if err := e.compile(n.Left, negated); err != nil {
+			return err
+		}
+		return e.compile(n.Right, negated)
+	case query.Not:
+		return e.compile(n.Node, !negated)
+	case query.And:
+		for _, c := range n.Nodes {
+			if err := e.compile(c, negated); err != nil {
+				return err
+			}
+		}
+		return nil
+	case query.Or:
+		for _, c := range n.Nodes {
+			if err := e.compile(c, negated); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown query node %T", n)
+	}
+
+	seq, ok := e.leaves[data]
+	if !ok {
+		q, err := text.Normalize([]byte(data))
+		if err != nil {
+			return fmt.Errorf("error normalizing query: %w", err)
+		}
+
+		terms, err := text.Tokenize(q)
+		if err != nil {
+			return fmt.Errorf("error tokenizing query: %w", err)
+		}
+
+		seq = sequence(terms)
+		e.leaves[data] = seq
+	}
+
+	for _, st := range seq {
+		for _, field := range fields {
+			e.terms[field] = appendUnique(e.terms[field], st.term)
+		}
+		if !negated {
+			e.scored = true
+		}
+		if !negated && len(fields) > 1 {
+			e.positive = appendUnique(e.positive, st.term)
+		}
+	}
+
+	return nil
+}
+
+func appendUnique(s []string, v string) []string {
+	for _, x := range s {
+		if x == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
+
+func (e *evaluator) eval(ctx context.Context, n query.Node) (result, error) {
+	switch n := n.(type) {
+	case query.Term:
+		return e.leaf(index.Fields, e.leaves[n.Text]), nil
+	case query.Phrase:
+		return e.leaf(index.Fields, e.leaves[n.Text]), nil
+	case query.Field:
+		return e.field(ctx, n)
+	case query.Depth:
+		return e.depth(ctx, n)
+	case query.Near:
+		return e.near(n), nil
+	case query.Not:
+		return e.not(ctx, n)
+	case query.And:
+		return e.and(ctx, n)
+	case query.Or:
+		return e.or(ctx, n)
+	default:
+		return result{}, fmt.Errorf("unknown query node %T", n)
+	}
+}
+
+// starts returns where the tokenized term or phrase occurs in the field of the
+// page and its bm25 score there
+func (e *evaluator) starts(field string, seq []seqTerm, pageID int64) ([]uint32, float64, bool) {
+	first, ok := e.c.posting(field, seq[0].term, pageID)
+	if !ok {
+		return nil, 0, false
+	}
+
+	avgLength := e.c.stats.AvgLengths[field]
+
+	if len(seq) == 1 {
+		idf := e.c.terms[fieldTerm{field: field, term: seq[0].term}].idf
+		return first.positions, e.s.bm25(idf, first.count, first.length, avgLength), true
+	}
+
+	// a phrase is scored like a single term whose idf is the sum of the idf of
+	// its terms
+	positions := make([][]uint32, len(seq))
+	var phraseIDF float64
+	for i, st := range seq {
+		p, ok := e.c.posting(field, st.term, pageID)
+		if !ok {
+			return nil, 0, false
+		}
+		positions[i] = p.positions
+		phraseIDF += e.c.terms[fieldTerm{field: field, term: st.term}].idf
+	}
+
+	starts := phraseStarts(seq, positions)
+	if len(starts) == 0 {
+		return nil, 0, false
+	}
+
+	return starts, e.s.bm25(phraseIDF, int64(len(starts)), first.length, avgLength), true
+}
+
+// leaf matches a term or phrase in any of the fields, its score is the sum of
+// its weighted score in each. words that tokenize to more than one term are
+// matched as a phrase.
+func (e *evaluator) leaf(fields []string, seq []seqTerm) result {
+	if len(seq) == 0 {
+		return result{all: true}
+	}
+
+	ret := result{scores: map[int64]float64{}}
+
+	for _, field := range fields {
+		pl, ok := e.c.terms[fieldTerm{field: field, term: seq[0].term}]
+		if !ok {
+			continue
+		}
+
+		for pageID := range pl.pages {
+			if _, score, ok := e.starts(field, seq, pageID); ok {
+				ret.scores[pageID] += e.s.weight(field) * score
+			}
+		}
+	}
+
+	return ret
+}
+
+// near matches pages where both sides occur within the distance of each
+// other in the body, measured from the start of each
+func (e *evaluator) near(n query.Near) result {
+	left, right := e.leaves[leafText(n.Left)], e.leaves[leafText(n.Right)]
+
+	// if a side was ignored by the tokenizer, just match the other
+	switch {
+	case len(left) == 0:
+		return e.leaf(index.Fields, right)
+	case len(right) == 0:
+		return e.leaf(index.Fields, left)
+	}
+
+	ret := result{scores: map[int64]float64{}}
+
+	pl, ok := e.c.terms[fieldTerm{field: index.FieldBody, term: left[0].term}]
+	if !ok {
+		return ret
+	}
+
+	for pageID := range pl.pages {
+		a, aScore, ok := e.starts(index.FieldBody, left, pageID)
+		if !ok {
+			continue
+		}
+		b, bScore, ok := e.starts(index.FieldBody, right, pageID)
+		if !ok || minDistance(a, b) > n.Distance {
+			continue
+		}
+		ret.scores[pageID] = aScore + bScore
+	}
+
+	return ret
+}
+
+func leafText(n query.Node) string {
+	switch n := n.(type) {
+	case query.Term:
+		return n.Text
+	case query.Phrase:
+		return n.Text
+	default:
+		return ""
+	}
+}
+
+// listPages returns every indexed page, loading them the first time it is
+// called. stub pages, which have only been linked to, are left out.
+func (e *evaluator) listPages(ctx context.Context) ([]db.ListPagesRow, error) {
+	if e.pages != nil {
+		return e.pages, nil
+	}
+
+	pages, err := e.s.db.ListPages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing pages: %w", err)
+	}
+
+	e.pages = pages
+	for _, p := range pages {
+		e.c.pageranks[p.ID] = p.Pagerank
+	}
+
+	return pages, nil
+}
+
+// filter matches, without scoring them, the pages for which fn returns true
+func (e *evaluator) filter(ctx context.Context, fn func(db.ListPagesRow) bool) (result, error) {
+	pages, err := e.listPages(ctx)
+	if err != nil {
+		return result{}, err
+	}
+
+	ret := result{scores: map[int64]float64{}}
+	for _, p := range pages {
+		if fn(p) {
+			ret.scores[p.ID] = 0
+		}
+	}
+
+	return ret, nil
+}
+
+func (e *evaluator) field(ctx context.Context, n query.Field) (result, error) {
+	switch n.Name {
+	case "intitle":
+		return e.leaf([]string{index.FieldTitle}, e.leaves[n.Value]), nil
+	case "site":
+		// the value is a host, which also matches its subdomains, optionally
+		// followed by a path prefix
+		host, path, _ := strings.Cut(strings.ToLower(n.Value), "/")
+		path = "/" + path
+		return e.filter(ctx, func(p db.ListPagesRow) bool {
+			u, err := url.Parse(p.URL)
+			if err != nil {
+				return false
+			}
+			h := strings.ToLower(u.Hostname())
+			if h != host && !strings.HasSuffix(h, "."+host) {
+				return false
+			}
+			return strings.HasPrefix(strings.ToLower(u.Path), path) || (u.Path == "" && path == "/")
+		})
+	case "inurl":
+		value := strings.ToLower(n.Value)
+		return e.filter(ctx, func(p db.ListPagesRow) bool {
+			return strings.Contains(strings.ToLower(p.URL), value)
+		})
+	case "origin":
+		return e.origin(ctx, n.Value)
+	default:
+		return result{}, fmt.Errorf("unknown field %q", n.Name)
+	}
+}
+
+// origin matches, without scoring them, the pages discovered from the origin
+// url
+func (e *evaluator) origin(ctx context.Context, value string) (result, error) {
+	ret := result{scores: map[int64]float64{}}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		// it can't have been indexed
+		return ret, nil //nolint:nilerr
+	}
+
+	ids, err := e.s.db.GetPagesForOrigin(ctx, index.CleanURL(u).String())
+	if err != nil {
+		return result{}, fmt.Errorf("error getting pages for origin: %w", err)
+	}
+
+	for _, id := range ids {
+		ret.scores[id] = 0
+	}
+
+	return ret, nil
+}
+
+// depth matches, without scoring them, the pages whose depth satisfies the
+// comparison
+func (e *evaluator) depth(ctx context.Context, n query.Depth) (result, error) {
+	depth := int64(n.Depth)
+	return e.filter(ctx, func(p db.ListPagesRow) bool {
+		switch n.Op {
+		case "<":
+			return p.Depth < depth
+		case "<=":
+			return p.Depth <= depth
+		case ">":
+			return p.Depth > depth
+		case ">=":
+			return p.Depth >= depth
+		default:
+			return p.Depth == depth
+		}
+	})
+}
+
+// not matches every page that the node does not
+func (e *evaluator) not(ctx context.Context, n query.Not) (result, error) {
+	r, err := e.eval(ctx, n.Node)
+	if err != nil || r.all {
+		return r, err
+	}
+
+	return e.complement(ctx, r)
+}
+
+// complement returns every page that is not in r
+func (e *evaluator) complement(ctx context.Context, r result) (result, error) {
+	return e.filter(ctx, func(p db.ListPagesRow) bool {
+		_, ok := r.scores[p.ID]
+		return !ok
+	})
+}
+
+// and matches pages that match every node. negated nodes are subtracted from
+// the other matches rather than evaluated against every page.
+func (e *evaluator) and(ctx context.Context, n query.And) (result, error) {
+	var (
+		ret      = result{all: true}
+		excluded []result
+	)
+
+	for _, c := range n.Nodes {
+		if not, ok := c.(query.Not); ok {
+			r, err := e.eval(ctx, not.Node)
+			if err != nil {
+				return result{}, err
+			}
+			if !r.all {
+				excluded = append(excluded, r)
+			}
+			continue
+		}
+
+		r, err := e.eval(ctx, c)
+		if err != nil {
+			return result{}, err
+		}
+
+		switch {
+		case r.all:
+		case ret.all:
+			ret = r
+		default:
+			for pageID, score := range ret.scores {
+				if s, ok := r.scores[pageID]; ok {
+					ret.scores[pageID] = score + s
+				} else {
+					delete(ret.scores, pageID)
+				}
+			}
+		}
+	}
+
+	if len(excluded) == 0 {
+		return ret, nil
+	}
+
+	if ret.all {
+		// there was nothing but negations
+		union := result{scores: map[int64]float64{}}
+		for _, r := range excluded {
+			for pageID := range r.scores {
+				union.scores[pageID] = 0
+			}
+		}
+		return e.complement(ctx, union)
+	}
+
+	for _, r := range excluded {
+		for pageID := range r.scores {
+			delete(ret.scores, pageID)
+		}
+	}
+
+	return ret, nil
+}
+
+// or matches pages that match any of the nodes
+func (e *evaluator) or(ctx context.Context, n query.Or) (result, error) {
+	ret := result{all: true, scores: map[int64]float64{}}
+
+	for _, c := range n.Nodes {
+		r, err := e.eval(ctx, c)
+		if err != nil {
+			return result{}, err
+		}
+		if r.all {
+			continue
+		}
+
+		ret.all = false
+		for pageID, score := range r.scores {
+			ret.scores[pageID] += score
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/joshuarubin/brightwave-google/internal/text"
+)
+
+// seqTerm is a term within a phrase and its offset from the start of the
+// phrase
+type seqTerm struct {
+	term   string
+	offset uint32
+}
+
+// sequence flattens tokenized terms into the order they appeared, with offsets
+// relative to the first term
+func sequence(terms []text.Term) []seqTerm {
+	var ret []seqTerm
+	for _, t := range terms {
+		for _, p := range t.Positions {
+			ret = append(ret, seqTerm{term: t.Term, offset: p})
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].offset < ret[j].offset
+	})
+
+	if len(ret) > 0 {
+		start := ret[0].offset
+		for i := range ret {
+			ret[i].offset -= start
+		}
+	}
+
+	return ret
+}
+
+// phraseStarts returns the positions at which the phrase starts given the
+// positions of each of its terms in a page
+func phraseStarts(seq []seqTerm, positions [][]uint32) []uint32 {
+	var ret []uint32
+	for _, start := range positions[0] {
+		matched := true
+		for i, st := range seq[1:] {
+			if _, ok := slices.BinarySearch(positions[i+1], start+st.offset); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			ret = append(ret, start)
+		}
+	}
+	return ret
+}
+
+// minDistance returns the smallest distance between any position in a and any
+// position in b. both must be sorted.
+func minDistance(a, b []uint32) uint32 {
+	ret := ^uint32(0)
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if a[i] < b[j] {
+			ret = min(ret, b[j]-a[i])
+			i++
+		} else {
+			ret = min(ret, a[i]-b[j])
+			j++
+		}
+	}
+	return ret
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/joshuarubin/brightwave-google/internal/text"
+)
+
+func TestSequence(t *testing.T) {
+	// "to be or not to be", tokenized starting at position 10
+	seq := sequence([]text.Term{
+		{Term: "to", Positions: []uint32{10, 14}},
+		{Term: "be", Positions: []uint32{11, 15}},
+		{Term: "not", Positions: []uint32{13}},
+	})
+
+	want := []seqTerm{
+		{"to", 0}, {"be", 1}, {"not", 3}, {"to", 4}, {"be", 5},
+	}
+
+	if !slices.Equal(seq, want) {
+		t.Errorf("got %v, want %v", seq, want)
+	}
+
+	if seq := sequence(nil); seq != nil {
+		t.Errorf("got %v for no terms", seq)
+	}
+}
+
+func TestPhraseStarts(t *testing.T) {
+	tests := []struct {
+		name      string
+		seq       []seqTerm
+		positions [][]uint32
+		want      []uint32
+	}{{
+		name:      "adjacent",
+		seq:       []seqTerm{{"a", 0}, {"b", 1}},
+		positions: [][]uint32{{0, 5, 9}, {1, 7, 10}},
+		want:      []uint32{0, 9},
+	}, {
+		name:      "gap",
+		seq:       []seqTerm{{"a", 0}, {"c", 2}},
+		positions: [][]uint32{{3, 8}, {5, 9}},
+		want:      []uint32{3},
+	}, {
+		name:      "out of order",
+		seq:       []seqTerm{{"a", 0}, {"b", 1}},
+		positions: [][]uint32{{4}, {3}},
+		want:      nil,
+	}, {
+		name:      "repeated term",
+		seq:       []seqTerm{{"a", 0}, {"a", 1}},
+		positions: [][]uint32{{1, 2, 3}, {1, 2, 3}},
+		want:      []uint32{1, 2},
+	}, {
+		name:      "single term",
+		seq:       []seqTerm{{"a", 0}},
+		positions: [][]uint32{{2, 4}},
+		want:      []uint32{2, 4},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := phraseStarts(tt.seq, tt.positions); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinDistance(t *testing.T) {
+	tests := []struct {
+		a, b []uint32
+		want uint32
+	}{
+		{[]uint32{1}, []uint32{2}, 1},
+		{[]uint32{2}, []uint32{1}, 1},
+		{[]uint32{1, 20}, []uint32{10, 23}, 3},
+		{[]uint32{5}, []uint32{5}, 0},
+		{[]uint32{0, 100}, []uint32{40, 60}, 40},
+		{nil, []uint32{1}, ^uint32(0)},
+	}
+
+	for _, tt := range tests {
+		if got := minDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("minDistance(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
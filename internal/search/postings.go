@@ -0,0 +1,157 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+)
+
+// posting describes the occurrences of a term in a page
+type posting struct {
+	count     int64
+	length    int64
+	positions []uint32
+}
+
+// postingList contains every page a term occurs in
+type postingList struct {
+	idf   float64
+	pages map[int64]posting
+}
+
+// fieldTerm is a term indexed under a field
+type fieldTerm struct {
+	field string
+	term  string
+}
+
+func (ft fieldTerm) String() string {
+	return ft.field + ":" + ft.term
+}
+
+// snapshot is the state of the corpus that scores are computed against
+type snapshot struct {
+	NumPages   int64
+	MaxPageID  int64
+	AvgLengths map[string]float64 // by field
+	DocFreqs   map[string]int64   // by field and term
+}
+
+// snapshot returns the current state of the corpus. the db read lock must be
+// held.
+func (s *Search) snapshot(ctx context.Context) (snapshot, error) {
+	stats, err := s.db.GetCorpusStats(ctx)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("error getting corpus stats: %w", err)
+	}
+
+	fields, err := s.db.GetFieldStats(ctx)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("error getting field stats: %w", err)
+	}
+
+	ret := snapshot{
+		NumPages:   stats.NumPages,
+		MaxPageID:  stats.MaxPageID,
+		AvgLengths: make(map[string]float64, len(fields)),
+		DocFreqs:   map[string]int64{},
+	}
+
+	for _, f := range fields {
+		ret.AvgLengths[f.Field] = f.AvgLength
+	}
+
+	return ret, nil
+}
+
+// corpus is the part of the index needed to evaluate a query
+type corpus struct {
+	stats     snapshot
+	terms     map[fieldTerm]*postingList
+	pageranks map[int64]float64
+}
+
+// load the postings for each of the given terms, keyed by the field they are
+// indexed under. pages added after the snapshot was taken are ignored, and the
+// document frequencies of terms the snapshot doesn't have yet are added to it.
+// the db read lock must be held.
+func (s *Search) load(ctx context.Context, stats snapshot, fields map[string][]string) *corpus {
+	c := corpus{
+		stats:     stats,
+		terms:     map[fieldTerm]*postingList{},
+		pageranks: map[int64]float64{},
+	}
+
+	for field, terms := range fields {
+		for _, term := range terms {
+			c.load(ctx, s.db, fieldTerm{field: field, term: term})
+		}
+	}
+
+	return &c
+}
+
+func (c *corpus) load(ctx context.Context, d *db.DB, ft fieldTerm) {
+	if _, ok := c.terms[ft]; ok {
+		return
+	}
+
+	rows, err := d.GetPagesForTerm(ctx, db.GetPagesForTermParams{
+		Term:  ft.term,
+		Field: ft.field,
+	})
+	if err != nil {
+		slog.Warn("error getting pages for term", "error", err, "term", ft.term, "field", ft.field)
+		return
+	}
+
+	// there is a row for every origin of every page, collapse them to one
+	// posting per page
+	pl := postingList{pages: map[int64]posting{}}
+	for _, row := range rows {
+		if row.PageID > c.stats.MaxPageID {
+			continue
+		}
+
+		c.pageranks[row.PageID] = row.Pagerank
+
+		if _, ok := pl.pages[row.PageID]; ok {
+			continue
+		}
+
+		positions, err := index.DecodePositions(row.Positions)
+		if err != nil {
+			slog.Warn("error decoding positions", "error", err, "term", ft.term, "field", ft.field, "pageID", row.PageID)
+		}
+
+		pl.pages[row.PageID] = posting{
+			count:     row.Count,
+			length:    row.Length,
+			positions: positions,
+		}
+	}
+
+	// the document frequency of the term is frozen the first time it is
+	// loaded for the snapshot
+	df, ok := c.stats.DocFreqs[ft.String()]
+	if !ok {
+		df = int64(len(pl.pages))
+		c.stats.DocFreqs[ft.String()] = df
+	}
+
+	pl.idf = idf(c.stats.NumPages, df)
+	c.terms[ft] = &pl
+}
+
+// posting returns the posting of term, indexed under field, in the page
+func (c *corpus) posting(field, term string, pageID int64) (posting, bool) {
+	pl, ok := c.terms[fieldTerm{field: field, term: term}]
+	if !ok {
+		return posting{}, false
+	}
+	p, ok := pl.pages[pageID]
+	return p, ok
+}
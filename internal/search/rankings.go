@@ -0,0 +1,120 @@
+package search
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"maps"
+	"sync"
+	"time"
+)
+
+const (
+	// RankingTTL is how long the ranking computed for the first page of a
+	// search is kept for the pages that follow
+	RankingTTL = 10 * time.Minute
+
+	// MaxRankings is the number of rankings that are kept at once, the one
+	// closest to expiring is dropped to make room for another
+	MaxRankings = 1000
+
+	// MaxRankedPages is the total number of results kept across every
+	// ranking. the results of a search with more than this are scored again
+	// for each page, against the snapshot taken for the first.
+	MaxRankedPages = 1 << 20
+)
+
+// ranking is the ordered results of a search and the snapshot of the corpus
+// they were scored against
+type ranking struct {
+	query   string
+	stats   snapshot
+	pages   []RankedPage
+	expires time.Time
+}
+
+// rankings keeps the results of recent searches so that every page of results
+// is read from the same ranking, however the index changes in the meantime.
+// page tokens only refer to a ranking by id, the state itself never leaves the
+// server.
+type rankings struct {
+	mu      sync.Mutex
+	entries map[string]*ranking
+	pages   int // the number of results held by entries
+}
+
+func newRankingID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// get returns the ranking with the given id, if it was created for the same
+// query and hasn't expired. its pages are nil if there were too many to keep.
+func (r *rankings) get(id, query string) (ranking, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[id]
+	if !ok || e.query != query || time.Now().After(e.expires) {
+		return ranking{}, false
+	}
+
+	// the snapshot is added to as terms are loaded, so each search has its own
+	ret := *e
+	ret.stats.DocFreqs = maps.Clone(e.stats.DocFreqs)
+
+	return ret, true
+}
+
+// put stores the ranking with the given id, dropping its pages if they can't
+// fit. the rankings closest to expiring are dropped to make room.
+func (r *rankings) put(id, query string, stats snapshot, pages []RankedPage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	if r.entries == nil {
+		r.entries = map[string]*ranking{}
+	}
+
+	r.remove(id)
+
+	if len(pages) > MaxRankedPages {
+		pages = nil
+	}
+
+	for k, e := range r.entries {
+		if now.After(e.expires) {
+			r.remove(k)
+		}
+	}
+
+	for len(r.entries) >= MaxRankings || r.pages+len(pages) > MaxRankedPages {
+		var (
+			oldest string
+			first  time.Time
+		)
+		for k, e := range r.entries {
+			if oldest == "" || e.expires.Before(first) {
+				oldest, first = k, e.expires
+			}
+		}
+		r.remove(oldest)
+	}
+
+	r.entries[id] = &ranking{
+		query:   query,
+		stats:   stats,
+		pages:   pages,
+		expires: now.Add(RankingTTL),
+	}
+	r.pages += len(pages)
+}
+
+func (r *rankings) remove(id string) {
+	if e, ok := r.entries[id]; ok {
+		r.pages -= len(e.pages)
+		delete(r.entries, id)
+	}
+}
@@ -1,91 +1,168 @@
 package search
 
 import (
-	"container/heap"
 	"context"
 	"log/slog"
+	"sort"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/joshuarubin/brightwave-google/internal/db"
-	"github.com/joshuarubin/brightwave-google/internal/text"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/query"
 	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
 )
 
+// Config contains the search config
+type Config struct {
+	K1 float64 // bm25 term frequency saturation
+	B  float64 // bm25 document length normalization
+
+	// how much matches in each field count relative to matches in the body
+	TitleWeight       float64
+	DescriptionWeight float64
+	HeadingsWeight    float64
+	AnchorWeight      float64
+
+	// how much the importance of a page, from the link graph, boosts its score
+	PageRankWeight float64
+}
+
 type Search struct {
-	db *db.DB
+	db       *db.DB
+	cfg      Config
+	rankings rankings
 }
 
-func New(d *db.DB) *Search {
+func New(d *db.DB, cfg Config) *Search {
 	return &Search{
-		db: d,
+		db:  d,
+		cfg: cfg,
 	}
 }
 
-func (s *Search) Search(ctx context.Context, query string) (*pb.SearchResponse, error) {
-	q, err := text.Normalize([]byte(query))
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error normalizing query: %v", err)
+// ProximityWeight scales how much the closeness of matched terms to each other
+// boosts a page's score
+const ProximityWeight = 0.5
+
+// proximity returns how close the matched terms are to each other in the
+// page, from 0 (far apart) to 1 (adjacent)
+func proximity(c *corpus, pageID int64, terms []string) float64 {
+	var (
+		sum   float64
+		pairs int
+		prev  []uint32
+	)
+
+	for _, t := range terms {
+		p, ok := c.posting(index.FieldBody, t, pageID)
+		if !ok {
+			continue
+		}
+		if prev != nil {
+			if d := minDistance(prev, p.positions); d > 0 {
+				sum += 1 / float64(d)
+				pairs++
+			}
+		}
+		prev = p.positions
+	}
+
+	if pairs == 0 {
+		return 0
 	}
 
-	tokens, err := text.Tokenize(q)
+	return sum / float64(pairs)
+}
+
+const (
+	// DefaultPageSize is the number of results returned when the request
+	// doesn't specify a page size
+	DefaultPageSize = 25
+
+	// MaxPageSize is the largest number of results that can be returned at
+	// once
+	MaxPageSize = 100
+)
+
+func (s *Search) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	root, err := query.Parse(req.GetQuery())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error tokenizing query: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "error parsing query: %v", err)
+	}
+
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize == 0:
+		pageSize = DefaultPageSize
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	}
+
+	var cur cursor
+	if token := req.GetPageToken(); token != "" {
+		if cur, err = decodeCursor(token, req.GetQuery()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+
+	e := evaluator{
+		s:      s,
+		leaves: map[string][]seqTerm{},
+		terms:  map[string][]string{},
+	}
+
+	if err = e.compile(root, false); err != nil {
+		return nil, status.Errorf(codes.Internal, "error compiling query: %v", err)
 	}
 
 	s.db.RLock()
 	defer s.db.RUnlock()
 
-	// populate all the "RankedPage" items (those that matched at least one
-	// search term)
-	pages := map[int64]RankedPage{}
-	for _, tok := range tokens {
-		rows, err := s.db.GetPagesForTerm(ctx, tok)
-		if err != nil {
-			slog.Warn("error getting pages for term", "error", err, "term", tok)
-			continue
+	// later pages are read from the ranking computed for the first page. the
+	// results of searches with too many to keep are scored again for each
+	// page, against the snapshot taken for the first. should the ranking have
+	// expired, the results are scored against a new snapshot and resumed from
+	// the last one returned.
+	r, ok := s.rankings.get(cur.Ranking, req.GetQuery())
+	if !ok {
+		if r.stats, err = s.snapshot(ctx); err != nil {
+			return nil, status.Errorf(codes.Internal, "%v", err)
 		}
+		cur.Ranking = newRankingID()
+	}
 
-		for _, row := range rows {
-			page := pages[row.PageID]
-			if page.MatchedTerms == nil {
-				page.MatchedTerms = map[string]struct{}{}
-			}
-			if page.Origins == nil {
-				page.Origins = map[string]struct{}{}
-			}
-			page.PageID = row.PageID
-			page.NumMatchedTerms += row.Count
-			page.MatchedTerms[tok] = struct{}{}
-			page.Origins[row.Origin] = struct{}{}
-			pages[row.PageID] = page
+	pages := r.pages
+	if pages == nil {
+		if pages, err = s.rank(ctx, &e, root, r.stats); err != nil {
+			return nil, err
 		}
 	}
 
+	if !ok {
+		s.rankings.put(cur.Ranking, req.GetQuery(), r.stats, pages)
+	}
+
 	if len(pages) == 0 {
 		return nil, status.Errorf(codes.NotFound, "no results found")
 	}
 
-	// now build a max heap out of them that ranks them according to number of
-	// terms matched (i.e. relevance) and then number of unique origins (i.e.
-	// importance)
-	var rank PageRank
-	heap.Init(&rank)
-
-	for _, p := range pages {
-		heap.Push(&rank, &p)
+	// skip the results returned on previous pages
+	var start int
+	if req.GetPageToken() != "" {
+		start = sort.Search(len(pages), func(i int) bool {
+			return cur.after(&pages[i])
+		})
 	}
+	end := min(start+pageSize, len(pages))
 
-	// for the purposes of this exercise, we'll just return, at most, the top 10
-	// results
-
-	const MaxResults = 25
-	var resp pb.SearchResponse
-	resp.Triples = make([]*pb.Triple, min(rank.Len(), MaxResults))
+	resp := pb.SearchResponse{
+		Triples:               make([]*pb.Triple, 0, end-start),
+		TotalEstimatedResults: uint64(len(pages)),
+	}
 
-	for i := range resp.GetTriples() {
-		p := heap.Pop(&rank).(*RankedPage) //nolint:forcetypeassert
+	for _, p := range pages[start:end] {
 		dbPage, err := s.db.GetPage(ctx, p.PageID)
 		if err != nil {
 			slog.Warn("error getting page", "error", err, "pageID", p.PageID)
@@ -96,52 +173,98 @@ func (s *Search) Search(ctx context.Context, query string) (*pb.SearchResponse,
 			slog.Warn("error getting origins", "error", err, "pageID", p.PageID)
 			continue
 		}
-		resp.Triples[i] = &pb.Triple{
+		text, err := s.text(ctx, dbPage)
+		if err != nil {
+			slog.Warn("error getting page text", "error", err, "pageID", p.PageID)
+		}
+		resp.Triples = append(resp.Triples, &pb.Triple{
 			RelevantUrl: dbPage.URL,
 			OriginUrls:  origins,
 			Depth:       uint32(dbPage.Depth),
+			Score:       p.Score,
+			Snippet:     snippet(string(text), e.positive),
+			Title:       dbPage.Title,
+		})
+	}
+
+	if end < len(pages) {
+		last := pages[end-1]
+		next := cursor{
+			Ranking: cur.Ranking,
+			Score:   last.Score,
+			PageID:  last.PageID,
+		}
+		if resp.NextPageToken, err = next.encode(req.GetQuery()); err != nil {
+			return nil, status.Errorf(codes.Internal, "error encoding page token: %v", err)
 		}
 	}
 
 	return &resp, nil
 }
 
+// rank evaluates the query against the snapshot of the corpus and returns the
+// pages that match it, in rank order. the db read lock must be held.
+func (s *Search) rank(ctx context.Context, e *evaluator, root query.Node, stats snapshot) ([]RankedPage, error) {
+	e.c = s.load(ctx, stats, e.terms)
+
+	res, err := e.eval(ctx, root)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error evaluating query: %v", err)
+	}
+
+	// populate all the "RankedPage" items (those that matched the query) with
+	// their bm25 score
+	pages := make(PageRank, 0, len(res.scores))
+	for pageID, score := range res.scores {
+		if pageID > stats.MaxPageID {
+			// indexed after the snapshot was taken
+			continue
+		}
+
+		// pages whose matched terms are close together are more relevant
+		// and pages that are linked to by important pages are more important
+		pagerank := e.c.pageranks[pageID]
+		if e.scored {
+			score *= 1 + ProximityWeight*proximity(e.c, pageID, e.positive)
+			score *= 1 + s.cfg.PageRankWeight*pagerank
+		} else {
+			// queries with only filters, e.g. site:, match pages without
+			// scoring them, so they are ranked by importance alone
+			score = pagerank
+		}
+
+		pages = append(pages, RankedPage{
+			PageID: pageID,
+			Score:  score,
+		})
+	}
+
+	// now rank them according to their score (i.e. relevance and importance)
+	sort.Sort(pages)
+
+	return pages, nil
+}
+
 type RankedPage struct {
-	PageID          int64
-	NumMatchedTerms int64
-	MatchedTerms    map[string]struct{}
-	Origins         map[string]struct{}
+	PageID int64
+	Score  float64
 }
 
-type PageRank []*RankedPage
+type PageRank []RankedPage
 
 func (pq PageRank) Len() int { return len(pq) }
 
 func (pq PageRank) Less(i, j int) bool {
-	// rank the pages by the number of matching terms, then by
-	// the number of unique origins
-	if pq[i].NumMatchedTerms != pq[j].NumMatchedTerms {
-		return pq[i].NumMatchedTerms > pq[j].NumMatchedTerms
-	}
-
-	if len(pq[i].MatchedTerms) != len(pq[j].MatchedTerms) {
-		return len(pq[i].MatchedTerms) > len(pq[j].MatchedTerms)
+	// rank the pages by their score, which accounts for both their relevance
+	// and their pagerank. the page id makes the order total so that a page
+	// token can resume it from the last result returned.
+	if pq[i].Score != pq[j].Score {
+		return pq[i].Score > pq[j].Score
 	}
 
-	return len(pq[i].Origins) > len(pq[j].Origins)
+	return pq[i].PageID < pq[j].PageID
 }
 
 func (pq PageRank) Swap(i, j int) {
 	pq[i], pq[j] = pq[j], pq[i]
 }
-
-func (pq *PageRank) Push(x any) {
-	*pq = append(*pq, x.(*RankedPage)) //nolint:forcetypeassert
-}
-
-func (pq *PageRank) Pop() any {
-	n := len(*pq)
-	item := (*pq)[n-1]
-	*pq = (*pq)[:n-1]
-	return item
-}
@@ -0,0 +1,251 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
+)
+
+// doc is a page to index for a test, along with its body
+type doc struct {
+	index.Page
+	body string
+}
+
+// newSearch returns a search of an index of the docs, by url
+func newSearch(t *testing.T, docs map[string]doc) (*Search, *db.DB) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	d, err := db.Init(ctx, filepath.Join(t.TempDir(), "test.db"), func(int, string, string, int64) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SQL.Close() })
+
+	i := index.New(d, time.Hour)
+
+	// pages are added in a fixed order, so that their ids are too
+	raws := make([]string, 0, len(docs))
+	for raw := range docs {
+		raws = append(raws, raw)
+	}
+	slices.Sort(raws)
+
+	for _, raw := range raws {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		page := docs[raw].Page
+		page.URL = *u
+		if page.Origin.Host == "" {
+			page.Origin = *u
+		}
+		if err = i.Add(ctx, page, []byte(docs[raw].body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return New(d, Config{
+		K1:                DefaultK1,
+		B:                 DefaultB,
+		TitleWeight:       DefaultTitleWeight,
+		DescriptionWeight: DefaultDescriptionWeight,
+		HeadingsWeight:    DefaultHeadingsWeight,
+		AnchorWeight:      DefaultAnchorWeight,
+		PageRankWeight:    DefaultPageRankWeight,
+	}), d
+}
+
+func search(t *testing.T, s *Search, q string) []string {
+	t.Helper()
+
+	resp, err := s.Search(context.Background(), &pb.SearchRequest{Query: q})
+	if err != nil {
+		t.Fatalf("%s: %v", q, err)
+	}
+
+	var urls []string
+	for _, tr := range resp.GetTriples() {
+		urls = append(urls, tr.GetRelevantUrl())
+	}
+	return urls
+}
+
+func TestFilterOnlyRanking(t *testing.T) {
+	s, d := newSearch(t, map[string]doc{
+		"https://example.com/a": {body: "apple"},
+		"https://example.com/b": {body: "banana"},
+		"https://example.com/c": {body: "cherry"},
+		"https://other.org/d":   {body: "date"},
+	})
+
+	for raw, pagerank := range map[string]float64{
+		"https://example.com/a": 0.1,
+		"https://example.com/b": 0.5,
+		"https://example.com/c": 0.3,
+		"https://other.org/d":   0.9,
+	} {
+		if _, err := d.SQL.Exec("UPDATE pages SET pagerank = ? WHERE url = ?", pagerank, raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// without terms to score them, pages are ranked by pagerank rather than
+	// by id
+	got := search(t, s, "site:example.com")
+	want := []string{"https://example.com/b", "https://example.com/c", "https://example.com/a"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestOperators(t *testing.T) {
+	origin, _ := url.Parse("https://example.com/news/a")
+
+	s, _ := newSearch(t, map[string]doc{
+		"https://example.com/news/a":      {Page: index.Page{Title: "Breaking news"}, body: "storm warning"},
+		"https://www.example.com/sport/b": {Page: index.Page{Title: "Football", Depth: 1, Origin: *origin}, body: "storm match"},
+		"https://other.org/news/c":        {Page: index.Page{Title: "Breaking weather", Depth: 2}, body: "storm front"},
+	})
+
+	for _, tt := range []struct {
+		query string
+		want  []string
+	}{
+		{"storm site:example.com", []string{"https://example.com/news/a", "https://www.example.com/sport/b"}},
+		{"storm site:example.com/news", []string{"https://example.com/news/a"}},
+		{"site:other.org", []string{"https://other.org/news/c"}},
+		{"storm inurl:sport", []string{"https://www.example.com/sport/b"}},
+		{"storm intitle:breaking", []string{"https://example.com/news/a", "https://other.org/news/c"}},
+		{`storm intitle:"breaking news"`, []string{"https://example.com/news/a"}},
+		{"storm origin:https://example.com/news/a", []string{"https://example.com/news/a", "https://www.example.com/sport/b"}},
+		{"storm depth:<=1", []string{"https://example.com/news/a", "https://www.example.com/sport/b"}},
+		{"storm depth:>1", []string{"https://other.org/news/c"}},
+		{"storm depth:=0", []string{"https://example.com/news/a"}},
+		{"storm -site:example.com", []string{"https://other.org/news/c"}},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			got := search(t, s, tt.query)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldWeights(t *testing.T) {
+	// every page has the term in its body, the fields it is also in decide
+	// their order
+	s, _ := newSearch(t, map[string]doc{
+		"https://example.com/body":        {body: "zebra lion tiger"},
+		"https://example.com/description": {Page: index.Page{Description: "zebra"}, body: "zebra lion tiger"},
+		"https://example.com/headings":    {Page: index.Page{Headings: "zebra"}, body: "zebra lion tiger"},
+		"https://example.com/title":       {Page: index.Page{Title: "zebra"}, body: "zebra lion tiger"},
+	})
+
+	got := search(t, s, "zebra")
+	want := []string{
+		"https://example.com/title",
+		"https://example.com/headings",
+		"https://example.com/description",
+		"https://example.com/body",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRankingsLimits(t *testing.T) {
+	var r rankings
+
+	stats := snapshot{DocFreqs: map[string]int64{"body:apple": 1}}
+
+	r.put("big", "q", stats, make([]RankedPage, MaxRankedPages+1))
+
+	got, ok := r.get("big", "q")
+	if !ok {
+		t.Fatal("ranking with too many pages to keep wasn't kept")
+	}
+	if got.pages != nil || got.stats.DocFreqs["body:apple"] != 1 {
+		t.Errorf("got %d pages and doc freqs %v, want only the snapshot", len(got.pages), got.stats.DocFreqs)
+	}
+
+	if _, ok = r.get("big", "other"); ok {
+		t.Error("ranking was returned for a different query")
+	}
+
+	for n := range MaxRankings + 10 {
+		r.put(fmt.Sprint(n), "q", stats, make([]RankedPage, 1))
+	}
+
+	if len(r.entries) > MaxRankings {
+		t.Errorf("kept %d rankings, want at most %d", len(r.entries), MaxRankings)
+	}
+
+	for n := range 20 {
+		r.put(fmt.Sprint("large", n), "q", stats, make([]RankedPage, MaxRankedPages/10))
+	}
+
+	if r.pages > MaxRankedPages {
+		t.Errorf("kept %d pages, want at most %d", r.pages, MaxRankedPages)
+	}
+
+	if _, ok = r.get("large19", "q"); !ok {
+		t.Error("newest ranking was dropped")
+	}
+}
+
+func TestPageTokenSize(t *testing.T) {
+	docs := map[string]doc{}
+	for n := range 3 {
+		docs[fmt.Sprintf("https://example.com/%d", n)] = doc{body: "alpha beta gamma delta epsilon"}
+	}
+	s, _ := newSearch(t, docs)
+
+	q := "alpha beta gamma delta epsilon"
+	resp, err := s.Search(context.Background(), &pb.SearchRequest{Query: q, PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the token refers to the state kept by the server, rather than carrying
+	// it, so it doesn't grow with the query
+	token := resp.GetNextPageToken()
+	if len(token) > 128 {
+		t.Errorf("got a page token of %d bytes", len(token))
+	}
+
+	var seen []string
+	for _, tr := range resp.GetTriples() {
+		seen = append(seen, tr.GetRelevantUrl())
+	}
+
+	for token != "" {
+		resp, err = s.Search(context.Background(), &pb.SearchRequest{Query: q, PageSize: 1, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tr := range resp.GetTriples() {
+			seen = append(seen, tr.GetRelevantUrl())
+		}
+		token = resp.GetNextPageToken()
+	}
+
+	slices.Sort(seen)
+	if len(seen) != 3 || len(slices.Compact(seen)) != 3 {
+		t.Errorf("walked %v, want each of the 3 pages once", seen)
+	}
+}
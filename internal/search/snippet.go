@@ -0,0 +1,131 @@
+package search
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/text"
+)
+
+// SnippetWords is the number of words in a snippet
+const SnippetWords = 30
+
+// text returns the text that a snippet of the page is taken from. stub pages
+// haven't been fetched, so the text of the anchors linking to them is used
+// instead.
+func (s *Search) text(ctx context.Context, page db.Page) ([]byte, error) {
+	if !page.Stub {
+		return index.DecompressText(page.Text)
+	}
+
+	texts, err := s.db.GetAnchorText(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.Join(texts, " … ")), nil
+}
+
+// snippet returns the window of the text containing the most distinct query
+// terms, then the most matches. words matching a term are wrapped in <b> tags
+// and everything else is html escaped.
+func snippet(data string, terms []string) string {
+	words := strings.Fields(data)
+	if len(words) == 0 {
+		return ""
+	}
+
+	want := make(map[string]struct{}, len(terms))
+	for _, t := range terms {
+		want[t] = struct{}{}
+	}
+
+	// the term each word is indexed as, if it is one of the query terms
+	matches := make([]string, len(words))
+	lemmas := map[string]string{}
+	for i, w := range words {
+		lemma, ok := lemmas[w]
+		if !ok {
+			lemma = lemmaOf(w)
+			lemmas[w] = lemma
+		}
+		if _, ok := want[lemma]; ok {
+			matches[i] = lemma
+		}
+	}
+
+	// slide a window over the words, tracking how many times each term occurs
+	// within it
+	var (
+		size      = min(SnippetWords, len(words))
+		counts    = map[string]int{}
+		total     int
+		best      int
+		bestTerms int
+		bestTotal int
+	)
+
+	for i := range words {
+		if m := matches[i]; m != "" {
+			counts[m]++
+			total++
+		}
+
+		if i >= size {
+			if m := matches[i-size]; m != "" {
+				if counts[m]--; counts[m] == 0 {
+					delete(counts, m)
+				}
+				total--
+			}
+		}
+
+		if i < size-1 {
+			continue
+		}
+
+		if len(counts) > bestTerms || (len(counts) == bestTerms && total > bestTotal) {
+			best, bestTerms, bestTotal = i-size+1, len(counts), total
+		}
+	}
+
+	var b strings.Builder
+	if best > 0 {
+		b.WriteString("… ")
+	}
+
+	for i := best; i < best+size; i++ {
+		if i > best {
+			b.WriteByte(' ')
+		}
+		if matches[i] != "" {
+			b.WriteString("<b>" + html.EscapeString(words[i]) + "</b>")
+		} else {
+			b.WriteString(html.EscapeString(words[i]))
+		}
+	}
+
+	if best+size < len(words) {
+		b.WriteString(" …")
+	}
+
+	return b.String()
+}
+
+// lemmaOf returns the term a word of page text is indexed as
+func lemmaOf(word string) string {
+	data, err := text.Normalize([]byte(word))
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+
+	lemma, err := text.Lemma(string(data))
+	if err != nil {
+		return ""
+	}
+
+	return lemma
+}
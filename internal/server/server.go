@@ -3,26 +3,36 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"net/url"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/spf13/cobra"
 
 	"github.com/joshuarubin/brightwave-google/internal/crawler"
 	"github.com/joshuarubin/brightwave-google/internal/db"
+	"github.com/joshuarubin/brightwave-google/internal/extract"
 	"github.com/joshuarubin/brightwave-google/internal/index"
+	"github.com/joshuarubin/brightwave-google/internal/links"
+	"github.com/joshuarubin/brightwave-google/internal/pagerank"
 	"github.com/joshuarubin/brightwave-google/internal/queue"
 	"github.com/joshuarubin/brightwave-google/internal/registrar"
+	"github.com/joshuarubin/brightwave-google/internal/robots"
+	"github.com/joshuarubin/brightwave-google/internal/scope"
 	"github.com/joshuarubin/brightwave-google/internal/search"
 	pb "github.com/joshuarubin/brightwave-google/pkg/proto/google/v1"
 )
@@ -34,8 +44,15 @@ type Config struct {
 	TLSKeyFile   string // filename
 	NumCrawlers  uint32
 	FetchTimeout time.Duration
+	MaxBodyBytes int64
 	DBFile       string
 	ReindexDur   time.Duration
+	RobotsTTL    time.Duration
+	HostDelay    time.Duration
+	MaxHostConns uint32
+	Queue        queue.Config
+	Search       search.Config
+	PageRank     pagerank.Config
 }
 
 func (c *Config) Flags(cmd *cobra.Command) {
@@ -44,8 +61,27 @@ func (c *Config) Flags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&c.TLSKeyFile, "tls-key", "", "tls server key file")
 	cmd.Flags().Uint32Var(&c.NumCrawlers, "num-crawlers", 1, "number of concurrent crawlers")
 	cmd.Flags().DurationVar(&c.FetchTimeout, "fetch-timeout", DefaultFetchTimeout, "timeout for fetching a page")
+	cmd.Flags().Int64Var(&c.MaxBodyBytes, "max-body-bytes", crawler.DefaultMaxBodyBytes, "maximum number of bytes of a page to read, after decompression, anything past it is ignored")
 	cmd.Flags().StringVar(&c.DBFile, "db-file", "db.sqlite3", "sqlite3 database file")
 	cmd.Flags().DurationVar(&c.ReindexDur, "reindex-duration", DefaultReindexDur, "reindex pages after this much time has elapsed")
+	cmd.Flags().DurationVar(&c.RobotsTTL, "robots-ttl", DefaultRobotsTTL, "how long to cache robots.txt files")
+	cmd.Flags().DurationVar(&c.HostDelay, "host-delay", DefaultHostDelay, "minimum delay between requests to the same host, robots.txt crawl-delay is used if it is longer")
+	cmd.Flags().Uint32Var(&c.MaxHostConns, "max-host-conns", DefaultMaxHostConns, "maximum number of concurrent connections to the same host")
+	cmd.Flags().DurationVar(&c.Queue.LeaseDur, "lease-duration", DefaultLeaseDur, "how long a crawler may hold a queued url before it is returned to the queue")
+	cmd.Flags().Uint32Var(&c.Queue.MaxAttempts, "max-attempts", DefaultMaxAttempts, "number of times to try fetching a url before moving it to the dead-letter table")
+	cmd.Flags().DurationVar(&c.Queue.RetryBaseDelay, "retry-base-delay", DefaultRetryBaseDelay, "delay before retrying a failed fetch, doubled on each attempt")
+	cmd.Flags().DurationVar(&c.Queue.RetryMaxDelay, "retry-max-delay", DefaultRetryMaxDelay, "maximum delay before retrying a failed fetch")
+	cmd.Flags().Float64Var(&c.Search.K1, "bm25-k1", search.DefaultK1, "bm25 term frequency saturation parameter")
+	cmd.Flags().Float64Var(&c.Search.B, "bm25-b", search.DefaultB, "bm25 document length normalization parameter")
+	cmd.Flags().Float64Var(&c.Search.TitleWeight, "title-weight", search.DefaultTitleWeight, "weight of title matches relative to body matches")
+	cmd.Flags().Float64Var(&c.Search.DescriptionWeight, "description-weight", search.DefaultDescriptionWeight, "weight of meta description matches relative to body matches")
+	cmd.Flags().Float64Var(&c.Search.HeadingsWeight, "headings-weight", search.DefaultHeadingsWeight, "weight of heading matches relative to body matches")
+	cmd.Flags().Float64Var(&c.Search.AnchorWeight, "anchor-weight", search.DefaultAnchorWeight, "weight of matches in the text of links to a page relative to body matches")
+	cmd.Flags().Float64Var(&c.Search.PageRankWeight, "pagerank-weight", search.DefaultPageRankWeight, "how much a page's pagerank, from 0 to 1, boosts its score")
+	cmd.Flags().Float64Var(&c.PageRank.Damping, "pagerank-damping", pagerank.DefaultDamping, "pagerank damping factor, the probability of following a link rather than jumping to a random page")
+	cmd.Flags().Uint32Var(&c.PageRank.Iterations, "pagerank-iterations", pagerank.DefaultIterations, "maximum number of pagerank iterations")
+	cmd.Flags().Float64Var(&c.PageRank.Tolerance, "pagerank-tolerance", pagerank.DefaultTolerance, "pagerank iteration stops once the total change in scores is below this")
+	cmd.Flags().DurationVar(&c.PageRank.Interval, "pagerank-interval", pagerank.DefaultInterval, "minimum time between pagerank computations as the link graph changes")
 }
 
 type callbackKey struct {
@@ -65,14 +101,23 @@ type Server struct {
 	queue     *queue.Queue
 	callbacks map[callbackKey][]registrar.Callback
 	search    *search.Search
+	pagerank  *pagerank.Ranker
+	links     *links.Links
 }
 
 const (
-	KeepaliveTime       = 30 * time.Second
-	KeepaliveTimeout    = 20 * time.Second
-	KeepaliveMinTime    = 15 * time.Second
-	DefaultFetchTimeout = 5 * time.Second
-	DefaultReindexDur   = 24 * time.Hour
+	KeepaliveTime         = 30 * time.Second
+	KeepaliveTimeout      = 20 * time.Second
+	KeepaliveMinTime      = 15 * time.Second
+	DefaultFetchTimeout   = 5 * time.Second
+	DefaultReindexDur     = 24 * time.Hour
+	DefaultRobotsTTL      = 24 * time.Hour
+	DefaultHostDelay      = time.Second
+	DefaultMaxHostConns   = 2
+	DefaultLeaseDur       = time.Minute
+	DefaultMaxAttempts    = 5
+	DefaultRetryBaseDelay = 30 * time.Second
+	DefaultRetryMaxDelay  = time.Hour
 )
 
 // New constructs a new Server
@@ -88,12 +133,21 @@ func New(ctx context.Context, cfg Config) (*Server, error) {
 		return nil, err
 	}
 
+	rc := robots.New(&http.Client{
+		Transport: crawler.NewTransport(),
+		Timeout:   cfg.FetchTimeout,
+	}, crawler.Agent, cfg.RobotsTTL)
+
 	srv.index = index.New(db, cfg.ReindexDur)
-	srv.queue = queue.New(db, srv.index, &srv)
-	srv.search = search.New(db)
+	srv.queue = queue.New(db, srv.index, rc, queue.NewScheduler(cfg.HostDelay, cfg.MaxHostConns), &srv, cfg.Queue)
+	srv.search = search.New(db, cfg.Search)
+	srv.pagerank = pagerank.New(db, &srv, cfg.PageRank)
+	srv.links = links.New(db)
+
+	extractors := extract.New(crawler.Agent)
 
 	for i := range srv.crawlers {
-		srv.crawlers[i] = crawler.New(i, cfg.FetchTimeout, srv.index, srv.queue)
+		srv.crawlers[i] = crawler.New(i, cfg.FetchTimeout, cfg.MaxBodyBytes, srv.index, srv.queue, rc, extractors)
 	}
 
 	opts := []grpc.ServerOption{
@@ -168,6 +222,9 @@ func (s *Server) Serve(ctx context.Context) error {
 		return err
 	}
 
+	go s.queue.Run(ctx)
+	go s.pagerank.Run(ctx)
+
 	for _, c := range s.crawlers {
 		go c.Run(ctx)
 	}
@@ -190,6 +247,12 @@ func (s *Server) GracefulStop() {
 	s.s.GracefulStop()
 }
 
+var scopeModes = map[pb.ScopeMode]scope.Mode{
+	pb.ScopeMode_SCOPE_MODE_UNSPECIFIED: scope.ModeAny,
+	pb.ScopeMode_SCOPE_MODE_HOST:        scope.ModeHost,
+	pb.ScopeMode_SCOPE_MODE_DOMAIN:      scope.ModeDomain,
+}
+
 func (s *Server) Index(ctx context.Context, req *pb.IndexRequest) (*pb.IndexResponse, error) {
 	u, err := url.Parse(req.GetOrigin())
 	if err != nil {
@@ -197,18 +260,126 @@ func (s *Server) Index(ctx context.Context, req *pb.IndexRequest) (*pb.IndexResp
 		return nil, err
 	}
 
-	err = s.queue.Add(ctx, queue.Msg{
-		URL:      *u,
+	mode, ok := scopeModes[req.GetScope().GetMode()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown scope mode: %v", req.GetScope().GetMode())
+	}
+
+	crawl := queue.Crawl{
 		Origin:   *u,
 		MaxDepth: req.GetK(),
-	})
-	if err != nil {
+		Scope: scope.Scope{
+			Mode:    mode,
+			Include: req.GetScope().GetInclude(),
+			Exclude: req.GetScope().GetExclude(),
+		},
+		MaxPages:        req.GetMaxPages(),
+		MaxPagesPerHost: req.GetMaxPagesPerHost(),
+	}
+
+	if req.Deadline != nil {
+		if err = req.GetDeadline().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid deadline: %v", err)
+		}
+		crawl.Deadline = req.GetDeadline().AsTime()
+	}
+
+	crawlID, err := s.queue.NewCrawl(ctx, crawl)
+	switch {
+	case errors.Is(err, scope.ErrInvalidScope), errors.Is(err, queue.ErrDeadlinePassed):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
 		return nil, err
 	}
 
-	return &pb.IndexResponse{}, nil
+	return &pb.IndexResponse{
+		CrawlId: crawlID,
+	}, nil
+}
+
+func (s *Server) GetCrawl(ctx context.Context, req *pb.GetCrawlRequest) (*pb.GetCrawlResponse, error) {
+	crawl, err := s.queue.CrawlStatus(ctx, req.GetCrawlId())
+	switch {
+	case errors.Is(err, queue.ErrCrawlNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	resp := pb.GetCrawlResponse{
+		Crawl: &pb.Crawl{
+			Id:     crawl.ID,
+			Origin: crawl.Origin.String(),
+			K:      crawl.MaxDepth,
+			Scope: &pb.Scope{
+				Include: crawl.Scope.Include,
+				Exclude: crawl.Scope.Exclude,
+			},
+			MaxPages:        crawl.MaxPages,
+			MaxPagesPerHost: crawl.MaxPagesPerHost,
+			CreatedAt:       timestamppb.New(crawl.CreatedAt),
+			Pages:           uint32(crawl.Pages),
+			Queued:          uint32(crawl.Queued),
+			DeadLetters:     uint32(crawl.DeadLetters),
+			Truncated:       crawl.Truncated,
+		},
+	}
+
+	for m, mode := range scopeModes {
+		if mode == crawl.Scope.Mode {
+			resp.Crawl.Scope.Mode = m
+		}
+	}
+
+	if !crawl.Deadline.IsZero() {
+		resp.Crawl.Deadline = timestamppb.New(crawl.Deadline)
+	}
+
+	return &resp, nil
 }
 
 func (s *Server) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
-	return s.search.Search(ctx, req.GetQuery())
+	return s.search.Search(ctx, req)
+}
+
+func (s *Server) ListDeadLetters(ctx context.Context, _ *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	items, err := s.queue.DeadLetters(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing dead letters: %v", err)
+	}
+
+	var resp pb.ListDeadLettersResponse
+	resp.DeadLetters = make([]*pb.DeadLetter, len(items))
+	for i, item := range items {
+		resp.DeadLetters[i] = &pb.DeadLetter{
+			Url:       item.URL,
+			Origin:    item.Origin,
+			Depth:     uint32(item.Depth),
+			MaxDepth:  uint32(item.MaxDepth),
+			Attempts:  uint32(item.Attempts),
+			LastError: item.LastError,
+			CreatedAt: timestamppb.New(item.CreatedAt),
+		}
+	}
+
+	return &resp, nil
+}
+
+func (s *Server) RedriveDeadLetters(ctx context.Context, req *pb.RedriveDeadLettersRequest) (*pb.RedriveDeadLettersResponse, error) {
+	n, err := s.queue.Redrive(ctx, req.GetUrls()...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error redriving dead letters: %v", err)
+	}
+
+	return &pb.RedriveDeadLettersResponse{
+		Count: uint32(n),
+	}, nil
+}
+
+func (s *Server) GetOutlinks(ctx context.Context, req *pb.GetOutlinksRequest) (*pb.GetOutlinksResponse, error) {
+	return s.links.Outlinks(ctx, req)
+}
+
+func (s *Server) GetBacklinks(ctx context.Context, req *pb.GetBacklinksRequest) (*pb.GetBacklinksResponse, error) {
+	return s.links.Backlinks(ctx, req)
 }
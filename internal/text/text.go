@@ -3,6 +3,7 @@ package text
 import (
 	"bytes"
 	"io"
+	"sync"
 	"unicode"
 
 	"github.com/aaaton/golem/v4"
@@ -35,7 +36,38 @@ func Normalize(data []byte) ([]byte, error) {
 	return io.ReadAll(r)
 }
 
-func Tokenize(data []byte) ([]string, error) {
+// getLemmatizer returns the lemmatizer shared by all callers, loading its
+// dictionary is expensive so it is only done once
+var getLemmatizer = sync.OnceValues(func() (*golem.Lemmatizer, error) {
+	return golem.New(en.New())
+})
+
+// Lemma returns the term that a single word, already normalized, is indexed
+// as
+func Lemma(word string) (string, error) {
+	lemmatizer, err := getLemmatizer()
+	if err != nil {
+		return "", err
+	}
+	return lemmatizer.Lemma(word), nil
+}
+
+// Term is a token found in a document along with every position, in token
+// order, at which it occurred
+type Term struct {
+	Term      string
+	Positions []uint32
+}
+
+// Count returns the number of times the term occurred
+func (t Term) Count() int {
+	return len(t.Positions)
+}
+
+// Tokenize returns the lemmatized terms found in data, ordered by their first
+// occurrence. Positions count every token, including ignored ones, so that
+// the distance between terms is preserved.
+func Tokenize(data []byte) ([]Term, error) {
 	doc, err := prose.NewDocument(
 		string(data),
 		prose.WithSegmentation(false),
@@ -45,14 +77,15 @@ func Tokenize(data []byte) ([]string, error) {
 		return nil, err
 	}
 
-	lemmatizer, err := golem.New(en.New())
+	lemmatizer, err := getLemmatizer()
 	if err != nil {
 		return nil, err
 	}
 
-	tokens := map[string]struct{}{}
+	var ret []Term
+	terms := map[string]int{}
 
-	for _, tok := range doc.Tokens() {
+	for i, tok := range doc.Tokens() {
 		switch tok.Tag {
 		case "DT", "CC", "IN", "TO":
 			// ignore:
@@ -62,14 +95,15 @@ func Tokenize(data []byte) ([]string, error) {
 			// - infinitival to
 		default:
 			word := lemmatizer.Lemma(tok.Text)
-			tokens[word] = struct{}{}
+			j, ok := terms[word]
+			if !ok {
+				j = len(ret)
+				terms[word] = j
+				ret = append(ret, Term{Term: word})
+			}
+			ret[j].Positions = append(ret[j].Positions, uint32(i))
 		}
 	}
 
-	ret := make([]string, 0, len(tokens))
-	for t := range tokens {
-		ret = append(ret, t)
-	}
-
 	return ret, nil
 }
@@ -104,9 +104,44 @@ func (c *Client) Index(ctx context.Context, in *pb.IndexRequest) (*pb.IndexRespo
 	return c.client.Index(ctx, in)
 }
 
+func (c *Client) GetCrawl(ctx context.Context, in *pb.GetCrawlRequest) (*pb.GetCrawlResponse, error) {
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c.client.GetCrawl(ctx, in)
+}
+
 func (c *Client) Search(ctx context.Context, in *pb.SearchRequest) (*pb.SearchResponse, error) {
 	if err := c.dial(); err != nil {
 		return nil, err
 	}
 	return c.client.Search(ctx, in)
 }
+
+func (c *Client) ListDeadLetters(ctx context.Context, in *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c.client.ListDeadLetters(ctx, in)
+}
+
+func (c *Client) RedriveDeadLetters(ctx context.Context, in *pb.RedriveDeadLettersRequest) (*pb.RedriveDeadLettersResponse, error) {
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c.client.RedriveDeadLetters(ctx, in)
+}
+
+func (c *Client) GetOutlinks(ctx context.Context, in *pb.GetOutlinksRequest) (*pb.GetOutlinksResponse, error) {
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c.client.GetOutlinks(ctx, in)
+}
+
+func (c *Client) GetBacklinks(ctx context.Context, in *pb.GetBacklinksRequest) (*pb.GetBacklinksResponse, error) {
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c.client.GetBacklinks(ctx, in)
+}
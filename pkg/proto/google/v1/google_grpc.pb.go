@@ -19,8 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	GoogleService_Index_FullMethodName  = "/google.v1.GoogleService/Index"
-	GoogleService_Search_FullMethodName = "/google.v1.GoogleService/Search"
+	GoogleService_Index_FullMethodName              = "/google.v1.GoogleService/Index"
+	GoogleService_GetCrawl_FullMethodName           = "/google.v1.GoogleService/GetCrawl"
+	GoogleService_Search_FullMethodName             = "/google.v1.GoogleService/Search"
+	GoogleService_ListDeadLetters_FullMethodName    = "/google.v1.GoogleService/ListDeadLetters"
+	GoogleService_RedriveDeadLetters_FullMethodName = "/google.v1.GoogleService/RedriveDeadLetters"
+	GoogleService_GetOutlinks_FullMethodName        = "/google.v1.GoogleService/GetOutlinks"
+	GoogleService_GetBacklinks_FullMethodName       = "/google.v1.GoogleService/GetBacklinks"
 )
 
 // GoogleServiceClient is the client API for GoogleService service.
@@ -28,7 +33,12 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GoogleServiceClient interface {
 	Index(ctx context.Context, in *IndexRequest, opts ...grpc.CallOption) (*IndexResponse, error)
+	GetCrawl(ctx context.Context, in *GetCrawlRequest, opts ...grpc.CallOption) (*GetCrawlResponse, error)
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	RedriveDeadLetters(ctx context.Context, in *RedriveDeadLettersRequest, opts ...grpc.CallOption) (*RedriveDeadLettersResponse, error)
+	GetOutlinks(ctx context.Context, in *GetOutlinksRequest, opts ...grpc.CallOption) (*GetOutlinksResponse, error)
+	GetBacklinks(ctx context.Context, in *GetBacklinksRequest, opts ...grpc.CallOption) (*GetBacklinksResponse, error)
 }
 
 type googleServiceClient struct {
@@ -49,6 +59,16 @@ func (c *googleServiceClient) Index(ctx context.Context, in *IndexRequest, opts
 	return out, nil
 }
 
+func (c *googleServiceClient) GetCrawl(ctx context.Context, in *GetCrawlRequest, opts ...grpc.CallOption) (*GetCrawlResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCrawlResponse)
+	err := c.cc.Invoke(ctx, GoogleService_GetCrawl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *googleServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SearchResponse)
@@ -59,12 +79,57 @@ func (c *googleServiceClient) Search(ctx context.Context, in *SearchRequest, opt
 	return out, nil
 }
 
+func (c *googleServiceClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, GoogleService_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *googleServiceClient) RedriveDeadLetters(ctx context.Context, in *RedriveDeadLettersRequest, opts ...grpc.CallOption) (*RedriveDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RedriveDeadLettersResponse)
+	err := c.cc.Invoke(ctx, GoogleService_RedriveDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *googleServiceClient) GetOutlinks(ctx context.Context, in *GetOutlinksRequest, opts ...grpc.CallOption) (*GetOutlinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOutlinksResponse)
+	err := c.cc.Invoke(ctx, GoogleService_GetOutlinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *googleServiceClient) GetBacklinks(ctx context.Context, in *GetBacklinksRequest, opts ...grpc.CallOption) (*GetBacklinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBacklinksResponse)
+	err := c.cc.Invoke(ctx, GoogleService_GetBacklinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GoogleServiceServer is the server API for GoogleService service.
 // All implementations must embed UnimplementedGoogleServiceServer
 // for forward compatibility.
 type GoogleServiceServer interface {
 	Index(context.Context, *IndexRequest) (*IndexResponse, error)
+	GetCrawl(context.Context, *GetCrawlRequest) (*GetCrawlResponse, error)
 	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	RedriveDeadLetters(context.Context, *RedriveDeadLettersRequest) (*RedriveDeadLettersResponse, error)
+	GetOutlinks(context.Context, *GetOutlinksRequest) (*GetOutlinksResponse, error)
+	GetBacklinks(context.Context, *GetBacklinksRequest) (*GetBacklinksResponse, error)
 	mustEmbedUnimplementedGoogleServiceServer()
 }
 
@@ -78,9 +143,24 @@ type UnimplementedGoogleServiceServer struct{}
 func (UnimplementedGoogleServiceServer) Index(context.Context, *IndexRequest) (*IndexResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Index not implemented")
 }
+func (UnimplementedGoogleServiceServer) GetCrawl(context.Context, *GetCrawlRequest) (*GetCrawlResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrawl not implemented")
+}
 func (UnimplementedGoogleServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
+func (UnimplementedGoogleServiceServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedGoogleServiceServer) RedriveDeadLetters(context.Context, *RedriveDeadLettersRequest) (*RedriveDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedriveDeadLetters not implemented")
+}
+func (UnimplementedGoogleServiceServer) GetOutlinks(context.Context, *GetOutlinksRequest) (*GetOutlinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOutlinks not implemented")
+}
+func (UnimplementedGoogleServiceServer) GetBacklinks(context.Context, *GetBacklinksRequest) (*GetBacklinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBacklinks not implemented")
+}
 func (UnimplementedGoogleServiceServer) mustEmbedUnimplementedGoogleServiceServer() {}
 func (UnimplementedGoogleServiceServer) testEmbeddedByValue()                       {}
 
@@ -120,6 +200,24 @@ func _GoogleService_Index_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GoogleService_GetCrawl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCrawlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoogleServiceServer).GetCrawl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoogleService_GetCrawl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoogleServiceServer).GetCrawl(ctx, req.(*GetCrawlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GoogleService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SearchRequest)
 	if err := dec(in); err != nil {
@@ -138,6 +236,78 @@ func _GoogleService_Search_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GoogleService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoogleServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoogleService_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoogleServiceServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoogleService_RedriveDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedriveDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoogleServiceServer).RedriveDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoogleService_RedriveDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoogleServiceServer).RedriveDeadLetters(ctx, req.(*RedriveDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoogleService_GetOutlinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutlinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoogleServiceServer).GetOutlinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoogleService_GetOutlinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoogleServiceServer).GetOutlinks(ctx, req.(*GetOutlinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoogleService_GetBacklinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBacklinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoogleServiceServer).GetBacklinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoogleService_GetBacklinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoogleServiceServer).GetBacklinks(ctx, req.(*GetBacklinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GoogleService_ServiceDesc is the grpc.ServiceDesc for GoogleService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -149,10 +319,30 @@ var GoogleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Index",
 			Handler:    _GoogleService_Index_Handler,
 		},
+		{
+			MethodName: "GetCrawl",
+			Handler:    _GoogleService_GetCrawl_Handler,
+		},
 		{
 			MethodName: "Search",
 			Handler:    _GoogleService_Search_Handler,
 		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _GoogleService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "RedriveDeadLetters",
+			Handler:    _GoogleService_RedriveDeadLetters_Handler,
+		},
+		{
+			MethodName: "GetOutlinks",
+			Handler:    _GoogleService_GetOutlinks_Handler,
+		},
+		{
+			MethodName: "GetBacklinks",
+			Handler:    _GoogleService_GetBacklinks_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "google/v1/google.proto",